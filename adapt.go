@@ -0,0 +1,54 @@
+package eventually
+
+import "reflect"
+
+// WithPointerAdaptation makes the bus adapt event arguments to callbacks
+// expecting a pointer to the argument type, or the value pointed to by
+// a pointer argument.
+// Callbacks taking a pointer receive a pointer to a copy of the argument.
+// Callbacks are still verified against the event map without adaptation.
+func WithPointerAdaptation() Option {
+	return func(b *bus) {
+		b.pointerAdaptation = true
+	}
+}
+
+// adaptArguments adapts arguments differing from the callback parameters
+// by one level of pointer indirection.
+// The arguments are copied before being adapted, since they are shared
+// between listeners.
+func adaptArguments(callbackType reflect.Type, args []interface{}) []interface{} {
+	fixed := callbackType.NumIn()
+	if callbackType.IsVariadic() {
+		fixed--
+	}
+	var adapted []interface{}
+	for i := 0; i < len(args) && i < fixed; i++ {
+		arg := args[i]
+		if arg == nil {
+			continue
+		}
+		param := callbackType.In(i)
+		argType := reflect.TypeOf(arg)
+		var value reflect.Value
+		switch {
+		case argType == param:
+			continue
+		case reflect.PointerTo(argType) == param:
+			value = reflect.New(argType)
+			value.Elem().Set(reflect.ValueOf(arg))
+		case argType.Kind() == reflect.Ptr && argType.Elem() == param && !reflect.ValueOf(arg).IsNil():
+			value = reflect.ValueOf(arg).Elem()
+		default:
+			continue
+		}
+		if adapted == nil {
+			adapted = append([]interface{}{}, args...)
+		}
+		adapted[i] = value.Interface()
+	}
+	if adapted == nil {
+		return args
+	}
+	return adapted
+}
@@ -0,0 +1,33 @@
+package eventually_test
+
+import (
+	events "github.com/erkkah/eventually"
+	"testing"
+)
+
+type point struct {
+	x, y int
+}
+
+func TestWithPointerAdaptation(t *testing.T) {
+	b := events.NewBus(events.WithPointerAdaptation())
+
+	byPointer := make(chan *point, 1)
+	b.On("value", func(p *point) {
+		byPointer <- p
+	})
+	byValue := make(chan point, 1)
+	b.On("pointer", func(p point) {
+		byValue <- p
+	})
+
+	b.Post("value", point{1, 2})
+	if p := <-byPointer; p == nil || *p != (point{1, 2}) {
+		t.Fatalf("Expected pointer to posted value, got %v", p)
+	}
+
+	b.Post("pointer", &point{3, 4})
+	if p := <-byValue; p != (point{3, 4}) {
+		t.Fatalf("Expected value of posted pointer, got %v", p)
+	}
+}
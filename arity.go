@@ -0,0 +1,38 @@
+package eventually
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// WithArityInference makes a bus without an event map remember the
+// argument types of the first event posted to each topic, and reject
+// later events on the topic with other argument types, instead of
+// failing when the listeners are called.
+func WithArityInference() Option {
+	return func(b *bus) {
+		b.arities = make(map[string][]reflect.Type)
+	}
+}
+
+// inferArity checks event arguments against the first event posted
+// to the topic, if arity inference is enabled.
+// Called on the dispatcher goroutine.
+func (b *bus) inferArity(evnt event) error {
+	if b.arities == nil {
+		return nil
+	}
+	argTypes := make([]reflect.Type, len(evnt.data))
+	for i, arg := range evnt.data {
+		argTypes[i] = reflect.TypeOf(arg)
+	}
+	inferred, found := b.arities[evnt.topic]
+	if !found {
+		b.arities[evnt.topic] = argTypes
+		return nil
+	}
+	if !reflect.DeepEqual(inferred, argTypes) {
+		return fmt.Errorf("Message data mismatch, expected %v, got %v", inferred, argTypes)
+	}
+	return nil
+}
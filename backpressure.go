@@ -0,0 +1,49 @@
+package eventually
+
+// WithBackpressure makes the bus signal producers of a topic, through
+// BackpressureSignal, when a listener of the topic has more than n events
+// waiting to be delivered.
+// Listeners only build a backlog on buffered topics, see WithTopicBuffer
+// and WithListenerBuffer.
+func WithBackpressure(topic string, n int) Option {
+	return func(b *bus) {
+		if b.backpressure == nil {
+			b.backpressure = make(map[string]*backpressure)
+		}
+		b.backpressure[topic] = &backpressure{
+			threshold: n,
+			signal:    make(chan struct{}, 1),
+		}
+	}
+}
+
+type backpressure struct {
+	threshold int
+	signal    chan struct{}
+}
+
+func (b *bus) BackpressureSignal(topic string) <-chan struct{} {
+	if pressure, exists := b.backpressure[topic]; exists {
+		return pressure.signal
+	}
+	return nil
+}
+
+// signalBackpressure signals producers if a listener of the topic is
+// too far behind.
+// Called on the dispatcher goroutine.
+func (b *bus) signalBackpressure(topic string) {
+	pressure, exists := b.backpressure[topic]
+	if !exists {
+		return
+	}
+	for _, l := range b.topicListeners[topic] {
+		if len(l.channel) > pressure.threshold {
+			select {
+			case pressure.signal <- struct{}{}:
+			default:
+			}
+			return
+		}
+	}
+}
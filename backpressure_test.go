@@ -0,0 +1,47 @@
+package eventually_test
+
+import (
+	events "github.com/erkkah/eventually"
+	"testing"
+	"time"
+)
+
+func TestBackpressureSignal(t *testing.T) {
+	b := events.NewBus(
+		events.WithTopicBuffer("work", 10),
+		events.WithBackpressure("work", 3),
+	)
+
+	release := make(chan bool)
+	b.On("work", func(i int) {
+		<-release
+	})
+	signal := b.BackpressureSignal("work")
+
+	for i := 0; i < 3; i++ {
+		b.Post("work", i)
+	}
+	select {
+	case <-signal:
+		t.Fatal("Unexpected backpressure below threshold")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	signalled := false
+	for i := 3; i < 10 && !signalled; i++ {
+		b.Post("work", i)
+		select {
+		case <-signal:
+			signalled = true
+		default:
+		}
+	}
+	if !signalled {
+		t.Fatal("Expected backpressure signal")
+	}
+	close(release)
+
+	if b.BackpressureSignal("other") != nil {
+		t.Fatal("Expected no signal for topic without backpressure")
+	}
+}
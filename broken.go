@@ -0,0 +1,47 @@
+package eventually
+
+import "fmt"
+
+// recoverTopic recovers from a panic while delivering an event,
+// marking the topic as broken.
+// Deferred on the dispatcher goroutine.
+func (b *bus) recoverTopic(topic string, err *error) {
+	if x := recover(); x != nil {
+		*err = fmt.Errorf("Delivery failed on topic %q: %v", topic, x)
+		b.brokenTopics[topic] = *err
+	}
+}
+
+// publishRecovered publishes an event that is not part of a post, such as
+// a timer release, under the same recovery as sendEvent.
+// Events for broken topics, and delivery failures, are reported to the
+// error handler.
+func (b *bus) publishRecovered(evnt event) {
+	err, broken := b.brokenTopics[evnt.topic]
+	if !broken {
+		err = func() (err error) {
+			defer b.recoverTopic(evnt.topic, &err)
+			b.publish(evnt)
+			return nil
+		}()
+	}
+	if err != nil && b.errorHandler != nil {
+		callErrorHandler(b.errorHandler, evnt.topic, err)
+	}
+}
+
+func (b *bus) BrokenTopics() map[string]error {
+	broken := map[string]error{}
+	b.runQuery(func() {
+		for topic, err := range b.brokenTopics {
+			broken[topic] = err
+		}
+	})
+	return broken
+}
+
+func (b *bus) RestartTopic(topic string) {
+	b.runQuery(func() {
+		delete(b.brokenTopics, topic)
+	})
+}
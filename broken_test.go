@@ -0,0 +1,65 @@
+package eventually_test
+
+import (
+	events "github.com/erkkah/eventually"
+	"testing"
+	"time"
+)
+
+func TestBrokenTopics(t *testing.T) {
+	b := events.NewBus(events.WithTopicVerifier("bad", func(data []interface{}) error {
+		panic("Broken verifier")
+	}))
+
+	received := make(chan bool, 1)
+	b.On("good", func() {
+		received <- true
+	})
+
+	if err := b.Post("bad"); err == nil {
+		t.Fatal("Expected posting to failing topic to fail")
+	}
+	if err := b.Post("good"); err != nil {
+		t.Fatalf("Failed to post to healthy topic: %v", err)
+	}
+	<-received
+
+	broken := b.BrokenTopics()
+	if _, found := broken["bad"]; !found || len(broken) != 1 {
+		t.Fatalf("Expected only the failing topic to be broken, got %v", broken)
+	}
+	if err := b.Post("bad"); err != broken["bad"] {
+		t.Fatalf("Expected broken topic to reject events with %v, got %v", broken["bad"], err)
+	}
+
+	b.RestartTopic("bad")
+	if broken := b.BrokenTopics(); len(broken) != 0 {
+		t.Fatalf("Expected no broken topics after restart, got %v", broken)
+	}
+}
+
+func TestBrokenTopics_PostMulti(t *testing.T) {
+	b := events.NewBus(events.WithTopicVerifier("bad", func(data []interface{}) error {
+		panic("Broken verifier")
+	}))
+
+	received := make(chan bool, 2)
+	b.On("good", func() {
+		received <- true
+	})
+
+	if err := b.PostMulti([]string{"good", "bad"}); err == nil {
+		t.Fatal("Expected posting to failing topic to fail")
+	}
+	if _, found := b.BrokenTopics()["bad"]; !found {
+		t.Fatal("Expected the failing topic to be broken")
+	}
+	if err := b.PostMulti([]string{"good", "bad"}); err == nil {
+		t.Fatal("Expected posting to broken topic to fail")
+	}
+	select {
+	case <-received:
+		t.Fatal("No events should be delivered when a topic fails")
+	case <-time.After(10 * time.Millisecond):
+	}
+}
@@ -0,0 +1,140 @@
+package eventually
+
+// WithTopicBuffer lets n events be pending for each listener of a topic,
+// before delivery to the listener starts to block.
+// Does not apply to LIFO topics, which have unbounded buffers.
+func WithTopicBuffer(topic string, n int) Option {
+	return func(b *bus) {
+		if b.topicBuffers == nil {
+			b.topicBuffers = make(map[string]int)
+		}
+		b.topicBuffers[topic] = n
+	}
+}
+
+// WithListenerBuffer lets n events be pending for each listener, before
+// delivery to the listener starts to block, for topics without a buffer
+// set using WithTopicBuffer.
+//
+// Buffering lets the dispatcher move on while slow listeners catch up,
+// so that they hold up other listeners and posters less, at the cost of
+// memory for up to n pending events per listener. Each listener still
+// receives events in posting order, but listeners drift further apart,
+// and events posted before a listener is unsubscribed may still be
+// delivered to it.
+func WithListenerBuffer(n int) Option {
+	return func(b *bus) {
+		b.listenerBuffer = n
+	}
+}
+
+// bufferSize returns the number of events that can be pending for
+// each listener of a topic.
+func (b *bus) bufferSize(topic string) int {
+	if n, exists := b.topicBuffers[topic]; exists {
+		return n
+	}
+	return b.listenerBuffer
+}
+
+// Builder collects bus options using chainable methods, as a more
+// readable alternative to passing options to NewBus:
+//
+//	bus := NewBuilder().
+//		QueueLength(100).
+//		Topic("render").Buffer(10).Concurrency(1).
+//		Topic("log").Order(LIFO).
+//		Build()
+type Builder struct {
+	options []Option
+}
+
+// NewBuilder returns a builder for a bus with default settings.
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+// With adds options to the bus.
+func (b *Builder) With(options ...Option) *Builder {
+	b.options = append(b.options, options...)
+	return b
+}
+
+// QueueLength sets the queue length, see WithQueueLength.
+func (b *Builder) QueueLength(n int) *Builder {
+	return b.With(WithQueueLength(n))
+}
+
+// ListenerBuffer sets the default listener buffer, see WithListenerBuffer.
+func (b *Builder) ListenerBuffer(n int) *Builder {
+	return b.With(WithListenerBuffer(n))
+}
+
+// EventMap sets the event map, see WithEventMap.
+func (b *Builder) EventMap(eventMap EventMap) *Builder {
+	return b.With(WithEventMap(eventMap))
+}
+
+// MaxInFlight sets the bus wide concurrency limit, see WithMaxInFlight.
+func (b *Builder) MaxInFlight(n int) *Builder {
+	return b.With(WithMaxInFlight(n))
+}
+
+// Topic starts configuring a topic.
+func (b *Builder) Topic(topic string) *TopicBuilder {
+	return &TopicBuilder{
+		builder: b,
+		topic:   topic,
+	}
+}
+
+// Build creates a bus with the collected options.
+func (b *Builder) Build() Bus {
+	return NewBus(b.options...)
+}
+
+// TopicBuilder configures a topic of a bus being built.
+type TopicBuilder struct {
+	builder *Builder
+	topic   string
+}
+
+// Buffer sets the topic listener buffer, see WithTopicBuffer.
+func (t *TopicBuilder) Buffer(n int) *TopicBuilder {
+	t.builder.With(WithTopicBuffer(t.topic, n))
+	return t
+}
+
+// Concurrency sets the topic concurrency limit, see WithTopicConcurrency.
+func (t *TopicBuilder) Concurrency(n int) *TopicBuilder {
+	t.builder.With(WithTopicConcurrency(t.topic, n))
+	return t
+}
+
+// Order sets the topic delivery order, see WithTopicOrder.
+func (t *TopicBuilder) Order(order Order) *TopicBuilder {
+	t.builder.With(WithTopicOrder(t.topic, order))
+	return t
+}
+
+// Replay sets the number of events to replay, see WithReplay.
+func (t *TopicBuilder) Replay(n int) *TopicBuilder {
+	t.builder.With(WithReplay(t.topic, n))
+	return t
+}
+
+// Verifier sets the topic verifier, see WithTopicVerifier.
+func (t *TopicBuilder) Verifier(verifier func(data []interface{}) error) *TopicBuilder {
+	t.builder.With(WithTopicVerifier(t.topic, verifier))
+	return t
+}
+
+// Topic finishes configuring this topic, and starts configuring another.
+func (t *TopicBuilder) Topic(topic string) *TopicBuilder {
+	return t.builder.Topic(topic)
+}
+
+// Build finishes configuring this topic, and creates the bus.
+func (t *TopicBuilder) Build() Bus {
+	return t.builder.Build()
+}
@@ -0,0 +1,76 @@
+package eventually_test
+
+import (
+	events "github.com/erkkah/eventually"
+	"testing"
+	"time"
+)
+
+func TestBuilder(t *testing.T) {
+	b := events.NewBuilder().
+		QueueLength(20).
+		Topic("render").Buffer(10).Concurrency(2).
+		Topic("undo").Order(events.LIFO).
+		Build()
+
+	render := b.TopicMode("render")
+	if render.Buffer != 10 || render.Concurrency != 2 {
+		t.Fatalf("Unexpected render topic mode: %+v", render)
+	}
+	if undo := b.TopicMode("undo"); undo.Order != events.LIFO {
+		t.Fatalf("Unexpected undo topic mode: %+v", undo)
+	}
+
+	started := make(chan bool, 5)
+	release := make(chan bool)
+	listener, _ := b.On("render", func() {
+		started <- true
+		<-release
+	})
+	b.Post("render")
+	<-started
+	for i := 0; i < 4; i++ {
+		b.Post("render")
+	}
+	if backlog := b.ListenerBacklog(listener); backlog != 4 {
+		t.Fatalf("Expected buffered backlog of 4, got %d", backlog)
+	}
+	close(release)
+}
+
+func TestWithListenerBuffer(t *testing.T) {
+	b := events.NewBus(
+		events.WithListenerBuffer(10),
+		events.WithTopicBuffer("unbuffered", 0),
+	)
+	defer b.Close()
+
+	release := make(chan bool)
+	b.On("work", func(i int) {
+		<-release
+	})
+	served := make(chan int, 5)
+	b.On("work", func(i int) {
+		served <- i
+	})
+
+	timeout := time.After(time.Second)
+	for i := 0; i < 5; i++ {
+		b.Post("work", i)
+	}
+	for i := 0; i < 5; i++ {
+		select {
+		case <-served:
+		case <-timeout:
+			t.Fatal("Slow listener held up other listeners")
+		}
+	}
+	close(release)
+
+	if buffer := b.TopicMode("work").Buffer; buffer != 10 {
+		t.Fatalf("Expected listener buffer 10, got %d", buffer)
+	}
+	if buffer := b.TopicMode("unbuffered").Buffer; buffer != 0 {
+		t.Fatalf("Expected topic buffer to override listener buffer, got %d", buffer)
+	}
+}
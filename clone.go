@@ -0,0 +1,12 @@
+package eventually
+
+func (b *bus) Clone() Bus {
+	options := append([]Option{}, b.options...)
+	var eventMap *EventMap
+	if err := b.runQuery(func() {
+		eventMap = b.eventMap
+	}); err == nil && eventMap != nil {
+		options = append(options, WithEventMap(*eventMap))
+	}
+	return NewBus(options...)
+}
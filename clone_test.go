@@ -0,0 +1,59 @@
+package eventually_test
+
+import (
+	events "github.com/erkkah/eventually"
+	"testing"
+)
+
+func TestClone(t *testing.T) {
+	b := events.NewBus(
+		events.WithEventMap(events.EventMap{
+			"hello": {"name"},
+			"slow":  {},
+			"fill":  {},
+		}),
+		events.WithQueueLength(1),
+		events.WithFullQueuePolicy(events.ReturnError),
+	)
+	b.On("hello", func(name string) {})
+
+	clone := b.Clone()
+	defer clone.Close()
+
+	if count := clone.SubscriberCount("hello"); count != 0 {
+		t.Fatalf("Expected clone to start without listeners, found %d", count)
+	}
+	if _, err := clone.On("hello", func(i int) {}); err == nil {
+		t.Fatal("Expected clone to check listeners against the event map")
+	}
+	if err := clone.Post("hello", 42); err == nil {
+		t.Fatal("Expected clone to check events against the event map")
+	}
+
+	release := stallBus(clone)
+	defer release()
+	if err := clone.Post("hello", "fred"); err != events.ErrQueueFull {
+		t.Fatalf("Expected clone to have the same queue length, got %v", err)
+	}
+}
+
+func TestClone_ReplacedEventMap(t *testing.T) {
+	b := events.NewBus(events.WithEventMap(events.EventMap{
+		"hello": {"name"},
+	}))
+	if _, err := b.SetEventMap(events.EventMap{"hello": {42}}); err != nil {
+		t.Fatalf("Failed to replace event map: %v", err)
+	}
+
+	clone := b.Clone()
+	if err := clone.Post("hello", 42); err != nil {
+		t.Fatalf("Expected clone to use the current event map: %v", err)
+	}
+}
+
+func TestClone_View(t *testing.T) {
+	view := events.ReadOnly(events.NewBus())
+	if err := view.Clone().Post("ping"); err != events.ErrPostNotPermitted {
+		t.Fatalf("Expected clone of read-only view to be read-only, got %v", err)
+	}
+}
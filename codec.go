@@ -0,0 +1,117 @@
+package eventually
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Codec converts events to and from a serialized form, for passing
+// events outside of the process.
+type Codec interface {
+	// Encode serializes an event
+	Encode(topic string, data []interface{}) ([]byte, error)
+
+	// Decode deserializes an event encoded by Encode
+	Decode(frame []byte) (topic string, data []interface{}, err error)
+}
+
+// JSONCodec encodes events as JSON objects with "topic" and "data" fields.
+// Decoded event arguments have the types produced by encoding/json
+// when decoding into interface{} values.
+type JSONCodec struct{}
+
+type jsonEvent struct {
+	Topic string        `json:"topic"`
+	Data  []interface{} `json:"data"`
+}
+
+// Encode serializes an event to JSON
+func (JSONCodec) Encode(topic string, data []interface{}) ([]byte, error) {
+	if data == nil {
+		data = []interface{}{}
+	}
+	return json.Marshal(jsonEvent{topic, data})
+}
+
+// Decode deserializes a JSON event
+func (JSONCodec) Decode(frame []byte) (string, []interface{}, error) {
+	var evnt jsonEvent
+	if err := json.Unmarshal(frame, &evnt); err != nil {
+		return "", nil, err
+	}
+	return evnt.Topic, evnt.Data, nil
+}
+
+const (
+	uncompressedFrame byte = iota
+	compressedFrame
+)
+
+type compressingCodec struct {
+	codec     Codec
+	threshold int
+}
+
+// WithCompression wraps a codec, compressing encoded events larger than
+// threshold bytes using DEFLATE.
+// Each frame starts with a header byte telling if the rest of the frame
+// is compressed or not, so frames can only be decoded by a codec
+// wrapped the same way.
+func WithCompression(codec Codec, threshold int) Codec {
+	return compressingCodec{codec, threshold}
+}
+
+// Encode serializes an event using the wrapped codec, and compresses
+// the result if it is above the threshold
+func (c compressingCodec) Encode(topic string, data []interface{}) ([]byte, error) {
+	frame, err := c.codec.Encode(topic, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if len(frame) <= c.threshold {
+		buf.WriteByte(uncompressedFrame)
+		buf.Write(frame)
+		return buf.Bytes(), nil
+	}
+
+	buf.WriteByte(compressedFrame)
+	writer, err := flate.NewWriter(&buf, flate.DefaultCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := writer.Write(frame); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decode decompresses the frame if needed, and deserializes the event
+// using the wrapped codec
+func (c compressingCodec) Decode(frame []byte) (string, []interface{}, error) {
+	if len(frame) == 0 {
+		return "", nil, fmt.Errorf("Empty frame")
+	}
+
+	switch frame[0] {
+	case uncompressedFrame:
+		return c.codec.Decode(frame[1:])
+	case compressedFrame:
+		reader := flate.NewReader(bytes.NewReader(frame[1:]))
+		defer reader.Close()
+		decompressed, err := io.ReadAll(reader)
+		if err != nil {
+			return "", nil, err
+		}
+		return c.codec.Decode(decompressed)
+	default:
+		return "", nil, fmt.Errorf("Unknown frame header, %d", frame[0])
+	}
+}
@@ -0,0 +1,57 @@
+package eventually_test
+
+import (
+	events "github.com/erkkah/eventually"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestJSONCodec(t *testing.T) {
+	codec := events.JSONCodec{}
+
+	frame, err := codec.Encode("hello", []interface{}{"world", 42})
+	if err != nil {
+		t.Fatalf("Failed to encode event: %v", err)
+	}
+
+	topic, data, err := codec.Decode(frame)
+	if err != nil {
+		t.Fatalf("Failed to decode event: %v", err)
+	}
+	if topic != "hello" {
+		t.Fatalf("Unexpected topic %q", topic)
+	}
+	if !reflect.DeepEqual(data, []interface{}{"world", float64(42)}) {
+		t.Fatalf("Unexpected event data: %v", data)
+	}
+}
+
+func TestCompression(t *testing.T) {
+	codec := events.WithCompression(events.JSONCodec{}, 100)
+
+	large := strings.Repeat("large ", 1000)
+
+	for _, payload := range []string{"small", large} {
+		frame, err := codec.Encode("hello", []interface{}{payload})
+		if err != nil {
+			t.Fatalf("Failed to encode event: %v", err)
+		}
+
+		compressed := frame[0] != 0
+		if compressed != (payload == large) {
+			t.Fatalf("Unexpected compression flag %v for %d byte payload", compressed, len(payload))
+		}
+		if compressed && len(frame) >= len(large) {
+			t.Fatalf("Expected compressed frame, got %d bytes", len(frame))
+		}
+
+		topic, data, err := codec.Decode(frame)
+		if err != nil {
+			t.Fatalf("Failed to decode event: %v", err)
+		}
+		if topic != "hello" || !reflect.DeepEqual(data, []interface{}{payload}) {
+			t.Fatalf("Event did not survive round trip")
+		}
+	}
+}
@@ -0,0 +1,14 @@
+package eventually
+
+import "strings"
+
+// errorList is an error aggregating several errors.
+type errorList []error
+
+func (e errorList) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
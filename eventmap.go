@@ -0,0 +1,151 @@
+package eventually
+
+import (
+	"fmt"
+	"reflect"
+)
+
+func (b *bus) SetEventMap(eventMap EventMap) ([]Listener, error) {
+	var quarantined []Listener
+	var err error
+
+	closedErr := b.runQuery(func() {
+		previous := b.eventMap
+		b.eventMap = &eventMap
+
+		invalid := 0
+		for _, listeners := range b.topicListeners {
+			for _, l := range listeners {
+				if b.verifyListener(l) != nil {
+					invalid++
+				}
+			}
+		}
+
+		if invalid == 0 {
+			return
+		}
+
+		if !b.quarantine {
+			b.eventMap = previous
+			err = fmt.Errorf("%d listeners do not match the new event map", invalid)
+			return
+		}
+
+		for topic, listeners := range b.topicListeners {
+			keepList := []Listener{}
+			for _, l := range listeners {
+				if b.verifyListener(l) != nil {
+					b.dropListener(l, false)
+					quarantined = append(quarantined, l)
+				} else {
+					keepList = append(keepList, l)
+				}
+			}
+			b.setListeners(topic, keepList)
+		}
+	})
+	if closedErr != nil {
+		return nil, closedErr
+	}
+
+	return quarantined, err
+}
+
+// variadicArg marks the last argument of an event map topic as variadic.
+type variadicArg struct {
+	sample interface{}
+}
+
+// Variadic declares the last argument of an event map topic as variadic,
+// taking any number of arguments of the type of the sample value,
+// or of the sample type if it is a reflect.Type:
+//
+//	EventMap{"scores": {"name", Variadic(0)}}
+//
+// Listeners for variadic topics must have variadic callbacks,
+// like func(name string, scores ...int).
+func Variadic(sample interface{}) interface{} {
+	return variadicArg{sample}
+}
+
+func isVariadic(eventType []interface{}) bool {
+	if len(eventType) == 0 {
+		return false
+	}
+	_, variadic := eventType[len(eventType)-1].(variadicArg)
+	return variadic
+}
+
+// callbackTypesOf returns the callback argument types for an event type.
+func callbackTypesOf(eventType []interface{}) []reflect.Type {
+	if !isVariadic(eventType) {
+		return declaredTypesOf(eventType)
+	}
+	last := len(eventType) - 1
+	sample := eventType[last].(variadicArg).sample
+	return append(declaredTypesOf(eventType[:last]), reflect.SliceOf(declaredType(sample)))
+}
+
+// declaredType returns the type of an event map argument, declared
+// either by a reflect.Type or by a sample value.
+func declaredType(arg interface{}) reflect.Type {
+	if argType, isType := arg.(reflect.Type); isType {
+		return argType
+	}
+	return reflect.TypeOf(arg)
+}
+
+// declaredTypesOf returns the types of event map arguments.
+func declaredTypesOf(args []interface{}) []reflect.Type {
+	result := []reflect.Type{}
+	for _, arg := range args {
+		result = append(result, declaredType(arg))
+	}
+	return result
+}
+
+// matchesEventType checks event data against an event type.
+func matchesEventType(eventType []interface{}, data []interface{}) bool {
+	if !isVariadic(eventType) {
+		return len(data) == len(eventType) && assignableTo(data, declaredTypesOf(eventType))
+	}
+	last := len(eventType) - 1
+	if len(data) < last {
+		return false
+	}
+	if !assignableTo(data[:last], declaredTypesOf(eventType[:last])) {
+		return false
+	}
+	elemType := declaredType(eventType[last].(variadicArg).sample)
+	for _, arg := range data[last:] {
+		if !isAssignable(arg, elemType) {
+			return false
+		}
+	}
+	return true
+}
+
+// assignableTo checks that event arguments are assignable to the
+// corresponding argument types.
+func assignableTo(data []interface{}, argTypes []reflect.Type) bool {
+	for i, arg := range data {
+		if !isAssignable(arg, argTypes[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// isAssignable checks that an event argument is assignable to a type.
+// Nil arguments are assignable to all types that can be nil.
+func isAssignable(arg interface{}, argType reflect.Type) bool {
+	if arg == nil {
+		switch argType.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice, reflect.Chan, reflect.Func:
+			return true
+		}
+		return false
+	}
+	return reflect.TypeOf(arg).AssignableTo(argType)
+}
@@ -0,0 +1,204 @@
+package eventually_test
+
+import (
+	events "github.com/erkkah/eventually"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSetEventMap(t *testing.T) {
+	b := events.NewBus(events.WithEventMap(events.EventMap{
+		"hello": {"string", 42},
+	}))
+
+	b.On("hello", func(s string, i int) {})
+
+	_, err := b.SetEventMap(events.EventMap{
+		"hello": {"string"},
+	})
+	if err == nil {
+		t.Fatal("Replacing event map with mismatching listeners should fail")
+	}
+
+	if err := b.Post("hello", "foo", 12); err != nil {
+		t.Fatalf("Previous event map should still apply: %v", err)
+	}
+}
+
+func TestSetEventMap_Quarantine(t *testing.T) {
+	b := events.NewBus(
+		events.WithEventMap(events.EventMap{
+			"hello": {"string", 42},
+		}),
+		events.WithListenerQuarantine(),
+	)
+
+	received := make(chan string, 10)
+	old, _ := b.On("hello", func(s string, i int) {
+		received <- "old"
+	})
+
+	quarantined, err := b.SetEventMap(events.EventMap{
+		"hello": {"string"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to replace event map: %v", err)
+	}
+	if len(quarantined) != 1 || quarantined[0].Topic() != old.Topic() {
+		t.Fatalf("Expected the old listener to be quarantined, got %v", quarantined)
+	}
+
+	_, err = b.On("hello", func(s string) {
+		received <- "new"
+	})
+	if err != nil {
+		t.Fatalf("Failed to register callback: %v", err)
+	}
+
+	if err := b.Post("hello", "foo"); err != nil {
+		t.Fatalf("Failed to post event: %v", err)
+	}
+	if listener := <-received; listener != "new" {
+		t.Fatalf("Expected new listener to receive event, got %q", listener)
+	}
+}
+
+func TestWithArityInference(t *testing.T) {
+	b := events.NewBus(events.WithArityInference())
+
+	if err := b.Post("hello", "fred", 42); err != nil {
+		t.Fatalf("Failed to post first event: %v", err)
+	}
+	if err := b.Post("hello", "barney", 12); err != nil {
+		t.Fatalf("Failed to post matching event: %v", err)
+	}
+	if err := b.Post("hello", "fred"); err == nil {
+		t.Fatal("Posting event with other arity should fail")
+	}
+	if err := b.Post("hello", 42, "fred"); err == nil {
+		t.Fatal("Posting event with other argument types should fail")
+	}
+	if err := b.Post("goodbye", "fred"); err != nil {
+		t.Fatalf("Failed to post to other topic: %v", err)
+	}
+}
+
+func TestWithArityInference_PostMulti(t *testing.T) {
+	b := events.NewBus(events.WithArityInference())
+
+	if err := b.Post("hello", "fred"); err != nil {
+		t.Fatalf("Failed to post first event: %v", err)
+	}
+	if err := b.PostMulti([]string{"goodbye", "hello"}, 42); err == nil {
+		t.Fatal("Posting event with other arity to any topic should fail")
+	}
+	// The rejected batch must not have inferred the arity of "goodbye"
+	if err := b.Post("goodbye", "barney"); err != nil {
+		t.Fatalf("Failed to post to topic of rejected batch: %v", err)
+	}
+}
+
+func TestEventMap_Variadic(t *testing.T) {
+	b := events.NewBus(events.WithEventMap(events.EventMap{
+		"scores": {"name", events.Variadic(0)},
+	}))
+
+	received := make(chan int, 1)
+	_, err := b.On("scores", func(name string, scores ...int) {
+		received <- len(scores)
+	})
+	if err != nil {
+		t.Fatalf("Failed to register variadic callback: %v", err)
+	}
+
+	_, err = b.On("scores", func(name string) {})
+	if err == nil {
+		t.Fatal("Registering non-variadic callback for variadic topic should fail")
+	}
+
+	if err := b.Post("scores", "fred", 1, 2, 3); err != nil {
+		t.Fatalf("Failed to post variadic event: %v", err)
+	}
+	if count := <-received; count != 3 {
+		t.Fatalf("Expected 3 scores, got %d", count)
+	}
+
+	if err := b.Post("scores", "fred", "high"); err == nil {
+		t.Fatal("Posting mismatching variadic arguments should fail")
+	}
+}
+
+type document struct {
+	title string
+}
+
+func TestEventMap_DeclaredTypes(t *testing.T) {
+	b := events.NewBus(events.WithEventMap(events.EventMap{
+		"read":  {reflect.TypeOf((*io.Reader)(nil)).Elem()},
+		"saved": {reflect.TypeOf(&document{})},
+	}))
+
+	if _, err := b.On("read", func(r io.Reader) {}); err != nil {
+		t.Fatalf("Failed to register callback for interface type: %v", err)
+	}
+	if _, err := b.On("read", func(s string) {}); err == nil {
+		t.Fatal("Registering mismatching callback should fail")
+	}
+
+	saved := make(chan *document, 1)
+	if _, err := b.On("saved", func(doc *document) {
+		saved <- doc
+	}); err != nil {
+		t.Fatalf("Failed to register callback for pointer type: %v", err)
+	}
+	if err := b.Post("saved", &document{"notes"}); err != nil {
+		t.Fatalf("Failed to post pointer argument: %v", err)
+	}
+	if doc := <-saved; doc.title != "notes" {
+		t.Fatalf("Unexpected document: %v", doc)
+	}
+}
+
+func TestEventMap_NilAndInterfaceArguments(t *testing.T) {
+	b := events.NewBus(events.WithEventMap(events.EventMap{
+		"saved": {reflect.TypeOf(&document{})},
+		"read":  {reflect.TypeOf((*io.Reader)(nil)).Elem()},
+		"count": {0},
+	}))
+
+	saved := make(chan *document, 1)
+	b.On("saved", func(doc *document) {
+		saved <- doc
+	})
+	read := make(chan io.Reader, 1)
+	b.On("read", func(r io.Reader) {
+		read <- r
+	})
+
+	if err := b.Post("saved", nil); err != nil {
+		t.Fatalf("Failed to post nil pointer: %v", err)
+	}
+	if doc := <-saved; doc != nil {
+		t.Fatalf("Expected nil document, got %v", doc)
+	}
+
+	if err := b.Post("read", strings.NewReader("text")); err != nil {
+		t.Fatalf("Failed to post interface implementation: %v", err)
+	}
+	<-read
+	if err := b.Post("read", nil); err != nil {
+		t.Fatalf("Failed to post nil interface: %v", err)
+	}
+	if r := <-read; r != nil {
+		t.Fatalf("Expected nil reader, got %v", r)
+	}
+
+	if err := b.Post("read", 42); err == nil {
+		t.Fatal("Posting value not implementing the interface should fail")
+	}
+	if err := b.Post("count", nil); err == nil {
+		t.Fatal("Posting nil for a value type should fail")
+	}
+}
@@ -6,13 +6,25 @@
 package eventually
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // Bus is a simple channel based event bus.
 // Events are distributed asynchronously on named topic channels, with
 // a list of arbitrary arguments.
+//
+// Each listener receives events in the order they were accepted by the
+// bus, even with concurrent posters, except for LIFO topics and
+// buses using WithExecutor. With WithSyncDispatch, this only holds for
+// events from a single poster.
 type Bus interface {
 	// Once registers a callback that will receive at most one event.
 	// The callback is a function expecting the same arguments as
@@ -22,34 +34,368 @@ type Bus interface {
 	// On registers a callback that will receive all events until unsubscribed.
 	// The callback is a function expecting the same arguments as
 	// were passed with the event.
+	//
+	// Listeners can subscribe to all topics starting with a prefix using
+	// a wildcard pattern, such as "user.*". Wildcard callbacks receive the
+	// actual topic as their first argument, followed by the event arguments.
+	// Unless variadic, their event arguments must match all topics in the
+	// event map that match the pattern.
+	//
+	// Callbacks can return an error, which is passed to the error handler,
+	// or returned from Post when using WithSyncDispatch.
+	// Returned errors are dropped if no error handler is registered.
+	//
+	// Callbacks for Once and On can also take a context.Context as their
+	// first argument. The context is cancelled when the listener is
+	// unsubscribed or the bus is closed, letting long running callbacks
+	// stop early. Events still pending when the bus is closed are
+	// delivered with the cancelled context.
+	//
+	// Any value implementing EventHandler can be used instead of a
+	// callback function.
 	On(topic string, callback interface{}) (Listener, error)
 
+	// OnT is like On, using a Topic constant.
+	OnT(topic Topic, callback interface{}) (Listener, error)
+
+	// OnMany registers a callback like On, on each of the topics.
+	// If registering on any topic fails, the listeners already registered
+	// are unsubscribed, and the error is returned.
+	OnMany(topics []string, callback interface{}) ([]Listener, error)
+
+	// OnceAsync is like Once, but returns without waiting for the
+	// registration to be acknowledged by the bus.
+	// The registration result is delivered on the returned channel.
+	// Registration errors are also passed to the error handler, if any.
+	OnceAsync(topic string, callback interface{}) (Listener, <-chan error)
+
+	// OnAsync is like On, but returns without waiting for the
+	// registration to be acknowledged by the bus.
+	// The registration result is delivered on the returned channel.
+	// Registration errors are also passed to the error handler, if any.
+	OnAsync(topic string, callback interface{}) (Listener, <-chan error)
+
+	// OnWithID registers a callback that will receive all events until
+	// unsubscribed, together with the correlation id of each event.
+	// Event arguments are not checked against the event map.
+	OnWithID(topic string, callback func(id string, data ...interface{})) (Listener, error)
+
+	// OnSeq registers a callback that will receive all events until
+	// unsubscribed, together with the sequence number of each event.
+	// Sequence numbers are assigned to all events accepted by the bus,
+	// across all topics, starting at 1. They reflect the order in which
+	// events were posted.
+	// Event arguments are not checked against the event map.
+	OnSeq(topic string, callback func(seq uint64, data ...interface{})) (Listener, error)
+
+	// OnWithHeaders registers a callback that will receive all events
+	// until unsubscribed, together with the headers of each event.
+	// The headers must not be modified by the callback.
+	// Event arguments are not checked against the event map.
+	OnWithHeaders(topic string, callback func(headers map[string]string, data ...interface{})) (Listener, error)
+
+	// OnHeaderMatch registers a callback like On, that only receives
+	// events with headers containing all of the required headers.
+	OnHeaderMatch(topic string, required map[string]string, callback interface{}) (Listener, error)
+
+	// OnAfterQuiet registers a callback like On, for events on deliverTopic.
+	// Delivery is held until no events have been posted to quietTopic
+	// for the quiet duration.
+	// Held events do not hold up delivery to other listeners.
+	OnAfterQuiet(deliverTopic string, quietTopic string, quiet time.Duration, callback interface{}) (Listener, error)
+
+	// OnFiltered registers a callback like On, that only receives events
+	// for which the filter returns true. A nil filter accepts all events.
+	// The filter is called with the event arguments on the bus dispatcher,
+	// and should not block.
+	OnFiltered(topic string, filter func(data []interface{}) bool, callback interface{}) (Listener, error)
+
+	// OnSkipFirst registers a callback like On, that ignores the first
+	// n events posted to the topic after registration.
+	OnSkipFirst(topic string, n int, callback interface{}) (Listener, error)
+
+	// OnReduced registers a callback like On, that receives one event per
+	// window instead of every event. The window starts with the first
+	// event after the previous window, and the events posted during it
+	// are combined using reduce, starting with the arguments of the first
+	// event. The reducer is called on the bus dispatcher, and should not
+	// block.
+	OnReduced(topic string, window time.Duration, reduce func(acc, next []interface{}) []interface{}, callback interface{}) (Listener, error)
+
+	// OnWithReplaySince registers a callback like On, that only receives
+	// events with a sequence number above sinceSeq, typically the last
+	// one the caller has already seen. Replayed events that were already
+	// seen are skipped.
+	OnWithReplaySince(topic string, sinceSeq uint64, callback interface{}) (Listener, error)
+
+	// OnStruct registers a callback like On, taking a single struct
+	// populated from the event arguments. Events with a single
+	// map[string]interface{} argument populate fields by name, matching
+	// the "event" tag of each field or, ignoring case, its name.
+	// Events with a single argument of the struct type, such as those
+	// posted using PostStruct, are passed as is.
+	// Other events populate the exported fields in order.
+	// Event arguments are not checked against the event map.
+	OnStruct(topic string, callback interface{}) (Listener, error)
+
+	// OnTagged registers a callback like On, identified by a tag.
+	// Registering a callback with the same topic and tag as an existing
+	// listener replaces the existing listener.
+	OnTagged(topic string, tag string, callback interface{}) (Listener, error)
+
+	// OnGrouped registers a callback like On, as a member of a group of
+	// listeners for the topic. All callbacks in a group are called in
+	// registration order, one at a time, on a single shared goroutine.
+	OnGrouped(topic string, group string, callback interface{}) (Listener, error)
+
+	// SubscribeChan registers a caller owned channel that will receive
+	// the arguments of all events until unsubscribed.
+	// Delivery blocks while the channel is full.
+	// Unsubscribing stops delivery, but does not close the channel.
+	SubscribeChan(topic string, ch chan<- []interface{}) (Listener, error)
+
+	// Subscribe returns a channel with room for buffer events, that will
+	// receive the arguments of all events until unsubscribed.
+	// Delivery blocks while the channel is full.
+	// The channel is closed when the listener is unsubscribed or the bus
+	// is closed, after any pending events, ending loops ranging over it.
+	Subscribe(topic string, buffer int) (<-chan []interface{}, Listener, error)
+
 	// Post sends an event to all listeners for a specific topic
 	Post(topic string, data ...interface{}) error
 
-	// Unsubscribe removes previously registered topic callbacks
+	// PostT is like Post, using a Topic constant.
+	PostT(topic Topic, data ...interface{}) error
+
+	// PostWithID is like Post, but sets the correlation id of the event.
+	// Events posted without an id get an automatically generated one.
+	PostWithID(id string, topic string, data ...interface{}) error
+
+	// PostWithHeaders is like Post, but attaches headers to the event.
+	// Headers are passed to listeners registered using OnWithHeaders,
+	// and are not checked against the event map.
+	PostWithHeaders(topic string, headers map[string]string, data ...interface{}) error
+
+	// PostAt sends an event that takes effect at a given time, to a topic
+	// configured using WithTimeOrdering.
+	PostAt(effective time.Time, topic string, data ...interface{}) error
+
+	// PostSync is like Post, but waits until all listeners have handled
+	// the event. Errors from failing callbacks are returned, instead of
+	// being passed to the error handler.
+	// Events held while the bus is paused, or until their effective time,
+	// are not waited for.
+	PostSync(topic string, data ...interface{}) error
+
+	// PostNoWait sends an event without waiting for it to be accepted
+	// by the bus. Errors are passed to the error handler, if any.
+	PostNoWait(topic string, data ...interface{})
+
+	// PostMulti sends the same event to several topics.
+	// The event is verified against all topics before being delivered,
+	// and if any topic rejects it, it is not delivered to any topic.
+	PostMulti(topics []string, data ...interface{}) error
+
+	// PostStruct sends an event carrying a single struct value, to be
+	// received by listeners taking the struct type. Declare the topic in
+	// the event map using a struct sample, to have listeners and events
+	// checked against it.
+	// Returns ErrNotStruct if the value is not a struct.
+	PostStruct(topic string, event interface{}) error
+
+	// PostFunc sends an event with arguments produced by a function,
+	// that is only called if the topic has listeners.
+	// The function is called on the bus dispatcher, and should not block.
+	PostFunc(topic string, produce func() []interface{}) error
+
+	// WaitForListeners blocks until the topic has at least n listeners,
+	// or returns an error if that does not happen within the timeout.
+	WaitForListeners(topic string, n int, timeout time.Duration) error
+
+	// RateStats returns the rate of events posted to a topic,
+	// in events per second, over the trailing time window.
+	// Windows longer than MaxRateWindow are capped.
+	RateStats(topic string, window time.Duration) float64
+
+	// ListenerBacklog returns the number of events handed to a listener
+	// that it has not yet started processing.
+	ListenerBacklog(l Listener) int
+
+	// Stats returns delivery statistics for a topic,
+	// over the lifetime of the bus.
+	Stats(topic string) TopicStats
+
+	// SuccessRate returns the share of deliveries to listeners of a topic
+	// that succeeded, over the lifetime of the bus.
+	// Topics without any deliveries have a success rate of 1.
+	SuccessRate(topic string) float64
+
+	// PauseAll suspends delivery of events on all topics.
+	// Events posted while paused are verified as usual, and kept for
+	// delivery when resumed, unless WithDropWhilePaused is used.
+	PauseAll()
+
+	// ResumeAll resumes delivery of events, starting with the events
+	// posted while paused.
+	ResumeAll()
+
+	// SetEventMap replaces the event map of the bus.
+	// If existing listeners do not match the new event map, the event map
+	// is not replaced and an error is returned, unless the bus was created
+	// using WithListenerQuarantine.
+	SetEventMap(eventMap EventMap) ([]Listener, error)
+
+	// Diagnostics returns a snapshot of the resource usage of the bus.
+	Diagnostics() DiagnosticsInfo
+
+	// Listeners returns a snapshot of the registered listeners.
+	Listeners() []ListenerInfo
+
+	// SubscriberCount returns the number of listeners registered for
+	// a topic, or topic pattern.
+	SubscriberCount(topic string) int
+
+	// HasListeners returns true if events posted to the topic would be
+	// delivered to any listener, including wildcard listeners.
+	// Use it to avoid constructing expensive events that nobody listens to.
+	HasListeners(topic string) bool
+
+	// Audit compares the event map with the registered listeners.
+	Audit() AuditReport
+
+	// Topics returns the sorted topics, and topic patterns, that
+	// currently have listeners.
+	Topics() []string
+
+	// TopicMode describes how events on a topic are delivered,
+	// based on the bus configuration.
+	TopicMode(topic string) TopicModeInfo
+
+	// BackpressureSignal returns a channel that receives a value when a
+	// listener of the topic falls too far behind, as set using
+	// WithBackpressure, so that producers can slow down.
+	// Signals are not queued: a producer that has not yet received the
+	// last signal gets no new one.
+	// Returns nil for topics without backpressure.
+	BackpressureSignal(topic string) <-chan struct{}
+
+	// BrokenTopics returns the topics where delivering an event failed
+	// inside the bus, for example by a panicking topic verifier, with the
+	// error that broke them. Events posted to broken topics are rejected
+	// with that error, until the topic is restarted.
+	// Failures on one topic never affect delivery on other topics.
+	BrokenTopics() map[string]error
+
+	// RestartTopic resumes accepting events for a broken topic.
+	RestartTopic(topic string)
+
+	// UnsubscribeDrain removes a listener like Unsubscribe, but lets it
+	// process the events already handed to it, and waits until it has.
+	// This includes events handed to an executor, to a listener group,
+	// or to synchronous posters.
+	// Returns the context error if the context is done first.
+	UnsubscribeDrain(listener Listener, ctx context.Context) error
+
+	// Unsubscribe removes previously registered topic callbacks.
+	// Unsubscribing takes priority over pending events, so that
+	// removed listeners stop receiving events as soon as possible.
+	// Listeners registered using OnAsync or OnceAsync can be
+	// unsubscribed before their registration has been processed.
 	Unsubscribe(topic string, listener Listener)
 
+	// UnsubscribeFunc removes the first listener on the topic registered
+	// with the given callback function, for when the Listener is not at hand.
+	// Functions are compared by code pointer, so closures created by the
+	// same function literal are all considered equal.
+	// Does nothing if no listener matches.
+	UnsubscribeFunc(topic string, callback interface{})
+
 	// OnError registers a callback for receiving errors from
-	// listener panics.
+	// listener panics, and errors returned by listener callbacks.
 	// At most one error handler at a time can be registered.
 	// If no error handler is registered, panics leaked out from
-	// calling listener callbacks will cause a real panic.
+	// calling listener callbacks will cause a real panic, and
+	// returned errors are dropped.
 	OnError(callback func(topic string, err error))
+
+	// Close stops the bus and all its listeners.
+	// Events already handed to listeners are delivered before the
+	// listener goroutines exit.
+	// After closing, posting and subscribing returns ErrBusClosed.
+	// Closing an already closed bus has no effect.
+	Close() error
+
+	// Clone creates a new bus using the options this bus was created with,
+	// and its current event map, but without any listeners or events.
+	Clone() Bus
+
+	// Shutdown stops the bus like Close, but first handles all requests
+	// already queued, including posted events, and then waits for the
+	// listeners to finish delivering them, including deliveries
+	// submitted to an executor.
+	// Events held back by PauseAll, WithTimeOrdering, OnReduced or
+	// OnAfterQuiet are discarded.
+	// Returns the context error if the context is done before the bus
+	// has drained, leaving the bus closed.
+	Shutdown(ctx context.Context) error
 }
 
 type event struct {
-	topic string
-	data  []interface{}
+	topic     string
+	seq       uint64
+	id        string
+	headers   map[string]string
+	data      []interface{}
+	produce   func() []interface{}
+	effective time.Time
+	tracker   *deliveryTracker
+}
+
+// EventHandler can be registered in place of a callback function.
+// Handle is called with the event topic and data.
+type EventHandler interface {
+	Handle(topic string, data ...interface{})
 }
 
 // Listener is returned from Once and On calls and is used in Unsubscribe
 // calls to refer to registered callbacks.
 type Listener struct {
-	topic    string
-	once     bool
-	channel  chan []interface{}
-	callback reflect.Value
+	id          uint64
+	delivered   *uint64
+	outstanding *outstandingEvents
+	done        chan struct{}
+	topic       string
+	once        bool
+	wildcard    bool
+	withID      bool
+	withSeq     bool
+	withHeaders bool
+	handler     bool
+	structured  bool
+	tag         string
+	group       *listenerGroup
+	groupName   string
+	match       func(evnt *event) bool
+	hold        func(evnt *event) bool
+	gate        *quietGate
+	channel     chan []interface{}
+	sink        chan<- []interface{}
+	closeSink   bool
+	pending     *eventStack
+	callback    reflect.Value
+	invoke      func(args []interface{})
+	context     context.Context
+	cancel      context.CancelFunc
+}
+
+// ID returns the id of the listener, unique within its bus.
+func (l Listener) ID() uint64 {
+	return l.id
+}
+
+// Topic returns the topic, or topic pattern, the listener is registered for.
+func (l Listener) Topic() string {
+	return l.topic
 }
 
 type listenerRequest struct {
@@ -63,81 +409,451 @@ const (
 	addListenerReq requestType = iota
 	removeListenerReq
 	sendEventReq
+	waitListenersReq
+	queryReq
+	drainListenerReq
 )
 
 type busRequest struct {
 	request  requestType
 	event    event
 	listener Listener
+	count    int
+	query    func()
+	enqueued time.Time
 	errors   chan error
 }
 
+type listenerWaiter struct {
+	count  int
+	errors chan error
+}
+
 type bus struct {
-	queueLength    int
-	requests       chan busRequest
-	topicListeners map[string][]Listener
-	eventMap       *EventMap
-	errorHandler   func(topic string, err error)
+	// Accessed atomically, kept first for 64-bit alignment
+	listenerIDs uint64
+
+	goroutines  int32
+	outstanding int32
+	draining    int32
+	processed   uint64
+
+	queueLength       int
+	requests          chan busRequest
+	control           chan busRequest
+	closeOnce         sync.Once
+	pendingLock       sync.Mutex
+	pendingAdds       map[uint64]bool
+	closed            chan struct{}
+	done              chan struct{}
+	topicListeners    map[string][]Listener
+	patterns          map[string]bool
+	groups            map[string]*listenerGroup
+	sequence          uint64
+	paused            bool
+	pausedEvents      []event
+	brokenTopics      map[string]error
+	replays           map[string]*replayBuffer
+	timeOrdered       map[string]*timeQueue
+	quietGates        []*quietGate
+	metrics           func(Metric)
+	waiters           map[string][]listenerWaiter
+	rates             map[string]*rateCounter
+	countersLock      sync.Mutex
+	counters          map[string]*deliveryCounters
+	eventMap          *EventMap
+	verifiers         map[string]func(data []interface{}) error
+	arities           map[string][]reflect.Type
+	topicOrders       map[string]Order
+	topicConcurrency  map[string]int
+	topicBuffers      map[string]int
+	listenerBuffer    int
+	topicSlots        map[string]chan struct{}
+	backpressure      map[string]*backpressure
+	suggestTopics     bool
+	quarantine        bool
+	dropWhilePaused   bool
+	maxInFlight       int
+	maxPayload        int
+	codec             Codec
+	executor          Executor
+	syncDispatch      bool
+	fanOut            int
+	fullQueuePolicy   FullQueuePolicy
+	pointerAdaptation bool
+	syncDeliveries    []handoff
+	syncBacklog       syncBacklog
+	inFlight          chan struct{}
+	errorHandler      func(topic string, err error)
+	deadLetter        func(topic string, data []interface{})
+	options           []Option
+	closeTopic        string
+
+	subscriptionContext  context.Context
+	slowListenerPolicy   SlowListenerPolicy
+	slowListenerDeadline time.Duration
 }
 
-func prepareArguments(generic []interface{}) (specific []reflect.Value) {
+func prepareArguments(callbackType reflect.Type, generic []interface{}) (specific []reflect.Value) {
 	specific = make([]reflect.Value, 0)
-	for _, arg := range generic {
-		specific = append(specific, reflect.ValueOf(arg))
+	for i, arg := range generic {
+		value := reflect.ValueOf(arg)
+		if arg == nil {
+			// Nil arguments are passed as the zero value of the parameter
+			value = reflect.Zero(parameterType(callbackType, i))
+		}
+		specific = append(specific, value)
 	}
 	return
 }
 
-func callListener(callback reflect.Value, evnt []interface{}) (err error) {
+// parameterType returns the type of the callback parameter
+// receiving argument i.
+func parameterType(callbackType reflect.Type, i int) reflect.Type {
+	last := callbackType.NumIn() - 1
+	if callbackType.IsVariadic() && i >= last {
+		return callbackType.In(last).Elem()
+	}
+	if i > last {
+		return reflect.TypeOf((*interface{})(nil)).Elem()
+	}
+	return callbackType.In(i)
+}
+
+func callListener(l Listener, evnt []interface{}) (err error) {
 	defer func() {
 		if x := recover(); x != nil {
-			err = fmt.Errorf("Failed to call listener %#v with %#v: %v", callback, evnt, x)
+			err = listenerPanic{fmt.Errorf("Failed to call listener %#v with %#v: %v", l.callback, evnt, x)}
 		}
 	}()
-	args := prepareArguments(evnt)
-	callback.Call(args)
+	if l.invoke != nil {
+		l.invoke(evnt)
+		return nil
+	}
+	args := prepareArguments(l.callback.Type(), evnt)
+	results := l.callback.Call(args)
+	if len(results) == 1 && !results[0].IsNil() {
+		return results[0].Interface().(error)
+	}
 	return nil
 }
 
-func (b *bus) registerListener(topic string, callback interface{}, callOnce bool) (Listener, error) {
-	if !(reflect.TypeOf(callback).Kind() == reflect.Func) {
+func (b *bus) newListener(topic string, callback interface{}, callOnce bool) Listener {
+	return b.startListener(buildListener(topic, callback, callOnce))
+}
+
+func buildListener(topic string, callback interface{}, callOnce bool) Listener {
+	if handler, isHandler := callback.(EventHandler); isHandler {
+		return buildHandlerListener(topic, handler, callOnce)
+	}
+	callbackType := reflect.TypeOf(callback)
+	if !(callbackType.Kind() == reflect.Func) {
 		panic("Listeners must be functions")
 	}
-
-	ch := make(chan []interface{})
+	if callbackType.NumOut() > 1 || (callbackType.NumOut() == 1 && callbackType.Out(0) != errorType) {
+		panic("Listeners must return nothing, or an error")
+	}
 
 	l := Listener{
 		topic:    topic,
 		once:     callOnce,
-		channel:  ch,
+		wildcard: isPattern(topic),
+		channel:  make(chan []interface{}),
 		callback: reflect.ValueOf(callback),
+		invoke:   fastInvoker(callback),
+	}
+	if l.callback.Type().NumIn() > 0 && l.callback.Type().In(0) == contextType {
+		l.context, l.cancel = context.WithCancel(context.Background())
+	}
+	return l
+}
+
+// buildHandlerListener builds a listener calling the Handle method
+// of an EventHandler.
+func buildHandlerListener(topic string, handler EventHandler, callOnce bool) Listener {
+	l := Listener{
+		topic:    topic,
+		once:     callOnce,
+		wildcard: isPattern(topic),
+		handler:  true,
+		channel:  make(chan []interface{}),
+		callback: reflect.ValueOf(handler.Handle),
+	}
+	if l.wildcard {
+		l.invoke = func(args []interface{}) {
+			handler.Handle(args[0].(string), args[1:]...)
+		}
+	} else {
+		l.invoke = func(args []interface{}) {
+			handler.Handle(topic, args...)
+		}
+	}
+	return l
+}
+
+var contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// remove stops delivery to the listener, and cancels the context
+// passed to running callbacks.
+func (l Listener) remove() {
+	l.close()
+	if l.cancel != nil {
+		l.cancel()
 	}
+}
+
+// send hands an event to the listener.
+func (l Listener) send(args []interface{}) {
+	if l.group != nil {
+		l.group.tasks <- groupTask{l, args}
+		return
+	}
+	l.channel <- args
+}
+
+// close stops delivery to the listener, after pending events.
+func (l Listener) close() {
+	if l.group != nil {
+		l.group.leave()
+	} else {
+		close(l.channel)
+	}
+	if l.outstanding.close() {
+		l.stopped()
+	}
+}
+
+// outstandingEvents counts the events handed to a listener that it has
+// not handled yet, so that the listener can be stopped once it is closed
+// and idle, however its events are delivered.
+type outstandingEvents struct {
+	lock   sync.Mutex
+	count  int
+	closed bool
+}
+
+// add counts an event handed to the listener.
+func (o *outstandingEvents) add() {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.count++
+}
+
+// done counts a handled event.
+// Returns true if the listener is closed, and this was its last event.
+func (o *outstandingEvents) done() bool {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.count--
+	return o.closed && o.count == 0
+}
+
+// close marks the listener as closed.
+// Returns true if the listener is idle.
+func (o *outstandingEvents) close() bool {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	if o.closed {
+		return false
+	}
+	o.closed = true
+	return o.count == 0
+}
+
+// stopped is called when a closed listener has handled all events
+// handed to it.
+func (l Listener) stopped() {
+	close(l.done)
+	if l.closeSink {
+		close(l.sink)
+	}
+	if l.cancel != nil {
+		l.cancel()
+	}
+}
 
-	go func(l Listener) {
+// identify assigns an id, and delivery tracking, to a new listener.
+func (b *bus) identify(l *Listener) {
+	if l.id == 0 {
+		l.id = atomic.AddUint64(&b.listenerIDs, 1)
+		l.delivered = new(uint64)
+		l.outstanding = &outstandingEvents{}
+		l.done = make(chan struct{})
+	}
+}
+
+func (b *bus) startListener(l Listener) Listener {
+	b.identify(&l)
+	if n := b.bufferSize(l.topic); n > 0 {
+		l.channel = make(chan []interface{}, n)
+	}
+	if b.executor != nil || b.syncDispatch {
+		return l
+	}
+	if b.topicOrders[l.topic] == LIFO {
+		return b.startStackedListener(l)
+	}
+	b.goListener(func() {
 		for {
 			evnt, alive := <-l.channel
 			if !alive {
 				break
 			}
-			if err := callListener(l.callback, evnt); err != nil {
-				if b.errorHandler != nil {
-					b.errorHandler(l.topic, err)
-				} else {
-					panic(err)
-				}
-			}
+			b.deliver(l, evnt)
 		}
-	}(l)
+	})
+	return l
+}
 
+// goListener runs a listener delivery function on a new goroutine.
+func (b *bus) goListener(run func()) {
+	atomic.AddInt32(&b.goroutines, 1)
+	go func() {
+		defer atomic.AddInt32(&b.goroutines, -1)
+		run()
+	}()
+}
+
+// deliver passes an event to a listener, on the listener goroutine.
+func (b *bus) deliver(l Listener, evnt []interface{}) {
+	evnt, tracker := untrack(evnt)
+	err := b.invoke(l, evnt)
+	b.handled(l)
+	if tracker != nil {
+		tracker.done(err)
+	} else if err != nil {
+		b.reportError(l.topic, err)
+	}
+}
+
+// handled is called when a listener is done with an event handed to it,
+// stopping the listener if it is closed and this was its last event.
+func (b *bus) handled(l Listener) {
+	atomic.AddInt32(&b.outstanding, -1)
+	if l.outstanding.done() {
+		l.stopped()
+	}
+}
+
+// invoke passes an event to a listener, and counts the delivery.
+func (b *bus) invoke(l Listener, evnt []interface{}) error {
+	if l.sink != nil {
+		l.sink <- evnt
+		atomic.AddUint64(l.delivered, 1)
+		b.countDelivery(l.topic, nil)
+		return nil
+	}
+	if l.context != nil {
+		evnt = append([]interface{}{l.context}, evnt...)
+	}
+	if b.pointerAdaptation && !l.handler && !l.structured {
+		evnt = adaptArguments(l.callback.Type(), evnt)
+	}
+	slots := b.topicSlots[l.topic]
+	if slots != nil {
+		slots <- struct{}{}
+	}
+	if b.inFlight != nil {
+		b.inFlight <- struct{}{}
+	}
+	err := callListener(l, evnt)
+	if b.inFlight != nil {
+		<-b.inFlight
+	}
+	if slots != nil {
+		<-slots
+	}
+	if err == nil {
+		atomic.AddUint64(l.delivered, 1)
+	}
+	b.countDelivery(l.topic, err)
+	return err
+}
+
+// typed returns true if the listener callback arguments
+// should be verified against the event map.
+func (l Listener) typed() bool {
+	return !l.withID && !l.withSeq && !l.withHeaders && !l.handler && !l.structured && l.sink == nil
+}
+
+// listenerPanic wraps the error recovered from a panicking listener.
+type listenerPanic struct {
+	error
+}
+
+// reportError passes a listener error to the error handler.
+// Without an error handler, panics are re-raised and returned
+// errors are dropped.
+func (b *bus) reportError(topic string, err error) {
+	if b.errorHandler != nil {
+		callErrorHandler(b.errorHandler, topic, err)
+	} else if panicked, isPanic := err.(listenerPanic); isPanic {
+		panic(panicked.error)
+	}
+}
+
+// callErrorHandler calls an error handler, ignoring panics so that
+// a failing handler does not stop the bus or its listeners.
+func callErrorHandler(handler func(topic string, err error), topic string, err error) {
+	defer func() {
+		recover()
+	}()
+	handler(topic, err)
+}
+
+func (b *bus) registerListener(topic string, callback interface{}, callOnce bool) (Listener, error) {
+	return b.addListenerRequest(b.newListener(topic, callback, callOnce))
+}
+
+func (b *bus) addListenerRequest(l Listener) (Listener, error) {
+	b.identify(&l)
 	errors := make(chan error)
 
-	b.requests <- busRequest{
+	err := b.enqueue(b.requests, busRequest{
 		request:  addListenerReq,
 		listener: l,
 		errors:   errors,
+	})
+	if err == nil {
+		err = b.await(errors)
 	}
+	if err != nil {
+		l.remove()
+	}
+	return l, err
+}
+
+func (b *bus) registerListenerAsync(topic string, callback interface{}, callOnce bool) (Listener, <-chan error) {
+	l := b.newListener(topic, callback, callOnce)
+	b.addPending(l.id)
+
+	result := make(chan error, 1)
 
-	return l, <-errors
+	go func() {
+		defer b.addProcessed(l.id)
+		errors := make(chan error)
+
+		err := b.enqueue(b.requests, busRequest{
+			request:  addListenerReq,
+			listener: l,
+			errors:   errors,
+		})
+		if err == nil {
+			err = b.await(errors)
+		}
+		if err != nil {
+			l.remove()
+		}
+		if err != nil && b.errorHandler != nil {
+			callErrorHandler(b.errorHandler, topic, err)
+		}
+		result <- err
+		close(result)
+	}()
+
+	return l, result
 }
 
 func (b *bus) Once(topic string, callback interface{}) (Listener, error) {
@@ -148,26 +864,394 @@ func (b *bus) On(topic string, callback interface{}) (Listener, error) {
 	return b.registerListener(topic, callback, false)
 }
 
+func (b *bus) OnMany(topics []string, callback interface{}) ([]Listener, error) {
+	listeners := make([]Listener, 0, len(topics))
+	for _, topic := range topics {
+		l, err := b.On(topic, callback)
+		if err != nil {
+			for _, registered := range listeners {
+				b.Unsubscribe(registered.topic, registered)
+			}
+			return nil, err
+		}
+		listeners = append(listeners, l)
+	}
+	return listeners, nil
+}
+
+func (b *bus) OnceAsync(topic string, callback interface{}) (Listener, <-chan error) {
+	return b.registerListenerAsync(topic, callback, true)
+}
+
+func (b *bus) OnAsync(topic string, callback interface{}) (Listener, <-chan error) {
+	return b.registerListenerAsync(topic, callback, false)
+}
+
 func (b *bus) Unsubscribe(topic string, listener Listener) {
-	b.requests <- busRequest{
+	b.enqueue(b.control, busRequest{
 		request:  removeListenerReq,
 		listener: listener,
+	})
+}
+
+func (b *bus) UnsubscribeFunc(topic string, callback interface{}) {
+	value := reflect.ValueOf(callback)
+	if value.Kind() != reflect.Func {
+		return
+	}
+	pointer := value.Pointer()
+	errors := make(chan error)
+	err := b.enqueue(b.control, busRequest{
+		request: queryReq,
+		query: func() {
+			for _, l := range b.topicListeners[topic] {
+				if !l.handler && l.callback.IsValid() && l.callback.Pointer() == pointer {
+					b.removeListener(l, false)
+					return
+				}
+			}
+		},
+		errors: errors,
+	})
+	if err == nil {
+		b.await(errors)
+	}
+}
+
+func (b *bus) UnsubscribeDrain(listener Listener, ctx context.Context) error {
+	errors := make(chan error)
+	err := b.enqueue(b.control, busRequest{
+		request:  drainListenerReq,
+		listener: listener,
+		errors:   errors,
+	})
+	if err == nil {
+		err = b.await(errors)
+	}
+	if err != nil || listener.done == nil {
+		return err
+	}
+	select {
+	case <-listener.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (b *bus) OnWithID(topic string, callback func(id string, data ...interface{})) (Listener, error) {
+	l := b.newListener(topic, callback, false)
+	l.withID = true
+	return b.addListenerRequest(l)
+}
+
+func (b *bus) OnSeq(topic string, callback func(seq uint64, data ...interface{})) (Listener, error) {
+	l := b.newListener(topic, callback, false)
+	l.withSeq = true
+	return b.addListenerRequest(l)
+}
+
+func (b *bus) OnWithHeaders(topic string, callback func(headers map[string]string, data ...interface{})) (Listener, error) {
+	l := b.newListener(topic, callback, false)
+	l.withHeaders = true
+	return b.addListenerRequest(l)
+}
+
+func (b *bus) OnHeaderMatch(topic string, required map[string]string, callback interface{}) (Listener, error) {
+	l := b.newListener(topic, callback, false)
+	matching := make(map[string]string, len(required))
+	for key, value := range required {
+		matching[key] = value
+	}
+	l.match = func(evnt *event) bool {
+		for key, value := range matching {
+			if header, found := evnt.headers[key]; !found || header != value {
+				return false
+			}
+		}
+		return true
+	}
+	return b.addListenerRequest(l)
+}
+
+func (b *bus) OnFiltered(topic string, filter func(data []interface{}) bool, callback interface{}) (Listener, error) {
+	l := b.newListener(topic, callback, false)
+	if filter != nil {
+		l.match = func(evnt *event) bool {
+			return filter(evnt.data)
+		}
+	}
+	return b.addListenerRequest(l)
+}
+
+func (b *bus) OnSkipFirst(topic string, n int, callback interface{}) (Listener, error) {
+	l := b.newListener(topic, callback, false)
+	// Only called on the dispatcher goroutine
+	skipped := 0
+	l.match = func(evnt *event) bool {
+		if skipped < n {
+			skipped++
+			return false
+		}
+		return true
+	}
+	return b.addListenerRequest(l)
+}
+
+func (b *bus) OnTagged(topic string, tag string, callback interface{}) (Listener, error) {
+	l := b.newListener(topic, callback, false)
+	l.tag = tag
+	return b.addListenerRequest(l)
+}
+
+func (b *bus) SubscribeChan(topic string, ch chan<- []interface{}) (Listener, error) {
+	l := Listener{
+		topic:    topic,
+		wildcard: isPattern(topic),
+		channel:  make(chan []interface{}),
+		sink:     ch,
 	}
+	return b.addListenerRequest(b.startListener(l))
+}
+
+func (b *bus) Subscribe(topic string, buffer int) (<-chan []interface{}, Listener, error) {
+	ch := make(chan []interface{}, buffer)
+	l := Listener{
+		topic:     topic,
+		wildcard:  isPattern(topic),
+		channel:   make(chan []interface{}),
+		sink:      ch,
+		closeSink: true,
+	}
+	l, err := b.addListenerRequest(b.startListener(l))
+	return ch, l, err
 }
 
 func (b *bus) Post(topic string, data ...interface{}) error {
-	evnt := event{
-		topic, data,
+	return b.postEvent(event{
+		topic: topic,
+		data:  data,
+	})
+}
+
+func (b *bus) PostWithID(id string, topic string, data ...interface{}) error {
+	return b.postEvent(event{
+		topic: topic,
+		id:    id,
+		data:  data,
+	})
+}
+
+func (b *bus) PostWithHeaders(topic string, headers map[string]string, data ...interface{}) error {
+	copied := make(map[string]string, len(headers))
+	for key, value := range headers {
+		copied[key] = value
+	}
+	return b.postEvent(event{
+		topic:   topic,
+		headers: copied,
+		data:    data,
+	})
+}
+
+func (b *bus) PostNoWait(topic string, data ...interface{}) {
+	_, err := b.enqueueEvent(busRequest{
+		request: sendEventReq,
+		event: event{
+			topic: topic,
+			data:  data,
+		},
+		enqueued: time.Now(),
+	})
+	if err != nil && b.errorHandler != nil {
+		callErrorHandler(b.errorHandler, topic, err)
+	}
+}
+
+func (b *bus) PostMulti(topics []string, data ...interface{}) error {
+	var err error
+	closedErr := b.runQuery(func() {
+		errs := errorList{}
+		inferred := []string{}
+		for _, topic := range topics {
+			if _, found := b.arities[topic]; b.arities != nil && !found {
+				inferred = append(inferred, topic)
+			}
+			if verifyErr := b.verifyMulti(event{topic: topic, data: data}); verifyErr != nil {
+				errs = append(errs, fmt.Errorf("%s: %v", topic, verifyErr))
+			}
+		}
+		if len(errs) > 0 {
+			// Rejected batches leave no inferred arities behind
+			for _, topic := range inferred {
+				delete(b.arities, topic)
+			}
+			err = errs
+			return
+		}
+		for _, topic := range topics {
+			if sendErr := b.sendEvent(event{topic: topic, data: data}); sendErr != nil {
+				errs = append(errs, fmt.Errorf("%s: %v", topic, sendErr))
+			}
+		}
+		if len(errs) > 0 {
+			err = errs
+		}
+	})
+	if closedErr != nil {
+		return closedErr
+	}
+	return err
+}
+
+// verifyMulti verifies one event of a PostMulti batch before any of
+// them are sent, recovering like sendEvent.
+func (b *bus) verifyMulti(evnt event) (err error) {
+	if err, broken := b.brokenTopics[evnt.topic]; broken {
+		return err
+	}
+	defer b.recoverTopic(evnt.topic, &err)
+	if err := b.verifyEvent(evnt); err != nil {
+		return err
 	}
+	return b.verifyPayloadSize(evnt)
+}
+
+func (b *bus) PostFunc(topic string, produce func() []interface{}) error {
+	return b.postEvent(event{
+		topic:   topic,
+		produce: produce,
+	})
+}
 
+func (b *bus) postEvent(evnt event) error {
+	if b.syncDispatch {
+		return b.postSync(evnt)
+	}
 	errors := make(chan error)
 
-	b.requests <- busRequest{
-		request: sendEventReq,
-		event:   evnt,
+	queued, err := b.enqueueEvent(busRequest{
+		request:  sendEventReq,
+		event:    evnt,
+		enqueued: time.Now(),
+		errors:   errors,
+	})
+	if !queued {
+		return err
+	}
+	return b.await(errors)
+}
+
+func (b *bus) WaitForListeners(topic string, n int, timeout time.Duration) error {
+	errors := make(chan error, 1)
+
+	err := b.enqueue(b.requests, busRequest{
+		request: waitListenersReq,
+		event:   event{topic: topic},
+		count:   n,
 		errors:  errors,
+	})
+	if err != nil {
+		return err
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-errors:
+		return err
+	case <-b.done:
+		return ErrBusClosed
+	case <-timer.C:
 	}
-	return <-errors
+
+	b.runQuery(func() {
+		b.removeWaiter(topic, errors)
+	})
+	// The waiter might have been satisfied before it was removed
+	select {
+	case err := <-errors:
+		return err
+	default:
+		return fmt.Errorf("Timed out waiting for %d listeners on %q", n, topic)
+	}
+}
+
+func (b *bus) RateStats(topic string, window time.Duration) float64 {
+	var rate float64
+	b.runQuery(func() {
+		if counter, exists := b.rates[topic]; exists {
+			rate = counter.rate(time.Now(), window)
+		}
+	})
+	return rate
+}
+
+func (b *bus) ListenerBacklog(l Listener) int {
+	var backlog int
+	b.runQuery(func() {
+		backlog = len(l.channel)
+		if l.pending != nil {
+			backlog += l.pending.size()
+		}
+	})
+	return backlog
+}
+
+// runQuery runs a function on the dispatcher goroutine, and
+// waits for it to finish.
+// Returns ErrBusClosed, without running the function, if the bus is closed.
+func (b *bus) runQuery(query func()) error {
+	errors := make(chan error)
+
+	err := b.enqueue(b.requests, busRequest{
+		request: queryReq,
+		query:   query,
+		errors:  errors,
+	})
+	if err != nil {
+		return err
+	}
+	return b.await(errors)
+}
+
+// enqueue hands a request to the dispatcher goroutine.
+// Returns ErrBusClosed if the bus is closed.
+func (b *bus) enqueue(queue chan busRequest, request busRequest) error {
+	select {
+	case <-b.closed:
+		return ErrBusClosed
+	default:
+	}
+	select {
+	case queue <- request:
+		return nil
+	case <-b.closed:
+		return ErrBusClosed
+	}
+}
+
+// await waits for the dispatcher goroutine to handle a request.
+// Returns ErrBusClosed if the bus is closed before the request is handled.
+func (b *bus) await(errors chan error) error {
+	select {
+	case err := <-errors:
+		return err
+	case <-b.done:
+		return ErrBusClosed
+	}
+}
+
+// ErrBusClosed is returned when using a closed bus.
+var ErrBusClosed = errors.New("Bus closed")
+
+func (b *bus) Close() error {
+	b.closeOnce.Do(func() {
+		close(b.closed)
+	})
+	<-b.done
+	return nil
 }
 
 func (b *bus) OnError(callback func(topic string, err error)) {
@@ -178,6 +1262,13 @@ func (b *bus) OnError(callback func(topic string, err error)) {
 type Option func(*bus)
 
 // EventMap describes event topics and associated argument types.
+// Argument types are declared using sample values, or reflect.Type values
+// for types that are awkward to sample, like interfaces:
+//
+//	EventMap{
+//		"user.created": {"name", 42},
+//		"file.opened": {reflect.TypeOf((*io.Reader)(nil)).Elem()},
+//	}
 type EventMap map[string][]interface{}
 
 // WithEventMap sets the event map to check listeners and events against.
@@ -187,8 +1278,37 @@ func WithEventMap(eventMap EventMap) Option {
 	}
 }
 
+// WithListenerQuarantine makes SetEventMap unsubscribe listeners that
+// do not match the new event map, instead of failing.
+// The unsubscribed listeners are returned from SetEventMap, so that
+// they can be replaced by matching listeners.
+func WithListenerQuarantine() Option {
+	return func(b *bus) {
+		b.quarantine = true
+	}
+}
+
+// WithErrorHandler sets the error handler of the bus, like OnError.
+func WithErrorHandler(handler func(topic string, err error)) Option {
+	return func(b *bus) {
+		b.errorHandler = handler
+	}
+}
+
+// WithDeadLetter sets a function to be called with events that were not
+// handed to any listener, including wildcard listeners, to make events
+// posted to misnamed topics visible.
+// The function is called on the bus dispatcher, in posting order,
+// and should not block.
+func WithDeadLetter(deadLetter func(topic string, data []interface{})) Option {
+	return func(b *bus) {
+		b.deadLetter = deadLetter
+	}
+}
+
 // WithQueueLength sets the internal queue length for bus communications.
-// When the queue is full, requests to the bus start to block.
+// When the queue is full, requests to the bus start to block,
+// unless posting is configured otherwise using WithFullQueuePolicy.
 // Defaults to 10.
 func WithQueueLength(length int) Option {
 	return func(b *bus) {
@@ -196,31 +1316,154 @@ func WithQueueLength(length int) Option {
 	}
 }
 
-func typesOf(args []interface{}) []reflect.Type {
-	result := []reflect.Type{}
-	for _, arg := range args {
-		argType := reflect.TypeOf(arg)
-		result = append(result, argType)
+// WithMaxInFlight limits the number of listener callbacks executing
+// concurrently across the whole bus.
+// Deliveries exceeding the limit wait for running callbacks to finish.
+// Defaults to 0, meaning no limit.
+func WithMaxInFlight(n int) Option {
+	return func(b *bus) {
+		b.maxInFlight = n
+	}
+}
+
+// WithTopicVerifier sets a function for verifying the arguments of
+// events posted to a topic.
+// The verifier replaces the event map check for the topic entirely,
+// and listeners for the topic are accepted without argument checks.
+// Events are rejected with the error returned by the verifier.
+func WithTopicVerifier(topic string, verifier func(data []interface{}) error) Option {
+	return func(b *bus) {
+		if b.verifiers == nil {
+			b.verifiers = make(map[string]func(data []interface{}) error)
+		}
+		b.verifiers[topic] = verifier
+	}
+}
+
+// WithSubscriptionContext makes the bus remove all listeners registered
+// while the context is active, when the context is done.
+// Listeners registered after that are not affected.
+func WithSubscriptionContext(ctx context.Context) Option {
+	return func(b *bus) {
+		b.subscriptionContext = ctx
+	}
+}
+
+// WithCodec sets the codec used for serializing events.
+// Defaults to JSONCodec.
+func WithCodec(codec Codec) Option {
+	return func(b *bus) {
+		b.codec = codec
+	}
+}
+
+// WithMaxPayloadBytes makes the bus reject events larger than n bytes,
+// as serialized by the bus codec, with ErrPayloadTooLarge.
+// Events that cannot be serialized are not checked.
+func WithMaxPayloadBytes(n int) Option {
+	return func(b *bus) {
+		b.maxPayload = n
+	}
+}
+
+// WithTopicSuggestions makes errors for unknown topics include the
+// closest topic in the event map, to help track down misspelled topics.
+func WithTopicSuggestions() Option {
+	return func(b *bus) {
+		b.suggestTopics = true
 	}
-	return result
 }
 
 func (b *bus) verifyListener(l Listener) error {
+	if l.wildcard {
+		return b.verifyWildcardListener(l)
+	}
+	if isReplyTopic(l.topic) {
+		return nil
+	}
+	if _, verified := b.verifiers[l.topic]; verified {
+		return nil
+	}
 	if b.eventMap == nil {
 		return nil
 	}
 	if eventType, found := (*b.eventMap)[l.topic]; found {
-		argTypes := typesOf(eventType)
-		expected := reflect.FuncOf(argTypes, []reflect.Type{}, false)
-		if l.callback.Type() != expected {
+		if !l.typed() {
+			return nil
+		}
+		argTypes := callbackTypesOf(eventType)
+		if l.context != nil {
+			argTypes = append([]reflect.Type{contextType}, argTypes...)
+		}
+		expected := reflect.FuncOf(argTypes, []reflect.Type{}, isVariadic(eventType))
+		expectedWithError := reflect.FuncOf(argTypes, []reflect.Type{errorType}, isVariadic(eventType))
+		if l.callback.Type() != expected && l.callback.Type() != expectedWithError {
 			return fmt.Errorf("Argument mismatch")
 		}
 		return nil
 	}
-	return fmt.Errorf("No such topic, %q", l.topic)
+	return b.unknownTopic(l.topic)
+}
+
+func (b *bus) unknownTopic(topic string) error {
+	if b.suggestTopics {
+		if suggestion, found := closestTopic(topic, *b.eventMap); found {
+			return fmt.Errorf("No such topic, %q; did you mean %q?", topic, suggestion)
+		}
+	}
+	return fmt.Errorf("No such topic, %q", topic)
+}
+
+func closestTopic(topic string, eventMap EventMap) (closest string, found bool) {
+	best := 0
+	for candidate := range eventMap {
+		distance := levenshtein(topic, candidate)
+		if !found || distance < best || (distance == best && candidate < closest) {
+			closest = candidate
+			best = distance
+			found = true
+		}
+	}
+	return
+}
+
+func levenshtein(a, b string) int {
+	s := []rune(a)
+	t := []rune(b)
+
+	previous := make([]int, len(t)+1)
+	current := make([]int, len(t)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := range s {
+		current[0] = i + 1
+		for j := range t {
+			cost := 1
+			if s[i] == t[j] {
+				cost = 0
+			}
+			current[j+1] = previous[j] + cost
+			if deletion := previous[j+1] + 1; deletion < current[j+1] {
+				current[j+1] = deletion
+			}
+			if insertion := current[j] + 1; insertion < current[j+1] {
+				current[j+1] = insertion
+			}
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(t)]
 }
 
 func (b *bus) addListener(l Listener) error {
+	if b.addProcessed(l.id) {
+		// Unsubscribed while the add request was queued
+		l.remove()
+		return nil
+	}
 	if err := b.verifyListener(l); err != nil {
 		return err
 	}
@@ -228,57 +1471,398 @@ func (b *bus) addListener(l Listener) error {
 	if !exists {
 		existing = make([]Listener, 0)
 	}
+	if l.tag != "" {
+		existing = b.removeTagged(existing, l.tag)
+	}
+	if l.groupName != "" {
+		l.group = b.joinGroup(l.topic, l.groupName)
+	}
+	if !b.replay(l) {
+		b.setListeners(l.topic, existing)
+		return nil
+	}
 	existing = append(existing, l)
 	b.topicListeners[l.topic] = existing
+	if l.wildcard {
+		b.patterns[l.topic] = true
+	}
+	if l.gate != nil {
+		b.quietGates = append(b.quietGates, l.gate)
+	}
+	b.listenersAdded(l.topic)
+	b.emit(ListenerAdded, l.topic, 1)
 	return nil
 }
 
-func (b *bus) removeListener(l Listener) {
-	if listeners, exists := b.topicListeners[l.topic]; exists {
+// removeTagged removes listeners with the given tag from a list of listeners.
+func (b *bus) removeTagged(listeners []Listener, tag string) []Listener {
+	keepList := []Listener{}
+	for _, l := range listeners {
+		if l.tag == tag {
+			b.dropListener(l, false)
+		} else {
+			keepList = append(keepList, l)
+		}
+	}
+	return keepList
+}
+
+// listenersAdded is called by the dispatcher whenever listeners
+// have been added to a topic.
+func (b *bus) listenersAdded(topic string) {
+	waiters, exists := b.waiters[topic]
+	if !exists {
+		return
+	}
+	count := len(b.topicListeners[topic])
+	keepList := []listenerWaiter{}
+	for _, w := range waiters {
+		if count >= w.count {
+			w.errors <- nil
+		} else {
+			keepList = append(keepList, w)
+		}
+	}
+	if len(keepList) == 0 {
+		delete(b.waiters, topic)
+	} else {
+		b.waiters[topic] = keepList
+	}
+}
+
+func (b *bus) addWaiter(topic string, w listenerWaiter) {
+	if len(b.topicListeners[topic]) >= w.count {
+		w.errors <- nil
+		return
+	}
+	b.waiters[topic] = append(b.waiters[topic], w)
+}
+
+// removeWaiter removes a timed out waiter, identified by its errors channel.
+func (b *bus) removeWaiter(topic string, errors chan error) {
+	keepList := []listenerWaiter{}
+	for _, w := range b.waiters[topic] {
+		if w.errors != errors {
+			keepList = append(keepList, w)
+		}
+	}
+	if len(keepList) == 0 {
+		delete(b.waiters, topic)
+	} else {
+		b.waiters[topic] = keepList
+	}
+}
+
+// removeListener removes a listener, letting it process its pending
+// events first if draining.
+func (b *bus) removeListener(removed Listener, drain bool) {
+	found := false
+	if listeners, exists := b.topicListeners[removed.topic]; exists {
 		keepList := []Listener{}
 		for _, l := range listeners {
-			if l.callback == l.callback {
-				close(l.channel)
-			} else {
+			if l.id != removed.id {
 				keepList = append(keepList, l)
+				continue
+			}
+			b.dropListener(l, drain)
+			found = true
+		}
+		b.setListeners(removed.topic, keepList)
+	}
+	if !found {
+		b.cancelPending(removed.id)
+	}
+}
+
+// addPending records that an add request for a listener is on its way
+// to the request queue, so that the listener can be unsubscribed before
+// the request is processed.
+func (b *bus) addPending(id uint64) {
+	b.pendingLock.Lock()
+	defer b.pendingLock.Unlock()
+	b.pendingAdds[id] = false
+}
+
+// cancelPending marks a pending add request as unsubscribed.
+func (b *bus) cancelPending(id uint64) {
+	b.pendingLock.Lock()
+	defer b.pendingLock.Unlock()
+	if _, pending := b.pendingAdds[id]; pending {
+		b.pendingAdds[id] = true
+	}
+}
+
+// addProcessed forgets a pending add request.
+// Returns true if the listener was unsubscribed while pending.
+func (b *bus) addProcessed(id uint64) bool {
+	b.pendingLock.Lock()
+	defer b.pendingLock.Unlock()
+	cancelled := b.pendingAdds[id]
+	delete(b.pendingAdds, id)
+	return cancelled
+}
+
+// setListeners replaces the listeners of a topic, forgetting
+// topics without listeners.
+func (b *bus) setListeners(topic string, listeners []Listener) {
+	if len(listeners) == 0 {
+		delete(b.topicListeners, topic)
+		delete(b.patterns, topic)
+		return
+	}
+	b.topicListeners[topic] = listeners
+}
+
+// dropListener stops delivery to a listener taken out of its topic,
+// letting it process its pending events first if draining.
+// All listener removals go through here.
+// Called on the dispatcher goroutine.
+func (b *bus) dropListener(l Listener, drain bool) {
+	if drain {
+		l.close()
+	} else {
+		l.remove()
+	}
+	if l.gate != nil {
+		b.removeQuietGate(l.gate)
+	}
+	b.emit(ListenerRemoved, l.topic, 1)
+}
+
+// ErrPayloadTooLarge is returned when posting events larger than
+// allowed by WithMaxPayloadBytes.
+var ErrPayloadTooLarge = errors.New("Payload too large")
+
+func (b *bus) verifyPayloadSize(evnt event) error {
+	if b.maxPayload <= 0 {
+		return nil
+	}
+	frame, err := b.codec.Encode(evnt.topic, evnt.data)
+	if err != nil {
+		return nil
+	}
+	if len(frame) > b.maxPayload {
+		return ErrPayloadTooLarge
+	}
+	return nil
+}
+
+func (b *bus) removeAllListeners() {
+	for topic, listeners := range b.topicListeners {
+		for _, l := range listeners {
+			b.dropListener(l, false)
+		}
+		delete(b.topicListeners, topic)
+	}
+}
+
+// closeAllListeners stops delivery to all listeners,
+// after their pending events, and cancels their contexts.
+func (b *bus) closeAllListeners() {
+	for topic, listeners := range b.topicListeners {
+		for _, l := range listeners {
+			b.dropListener(l, true)
+			if l.cancel != nil {
+				l.cancel()
 			}
 		}
-		b.topicListeners[l.topic] = keepList
+		delete(b.topicListeners, topic)
 	}
 }
 
 func (b *bus) verifyEvent(evnt event) error {
-	if b.eventMap == nil {
+	if verifier, verified := b.verifiers[evnt.topic]; verified {
+		return verifier(evnt.data)
+	}
+	if isReplyTopic(evnt.topic) {
 		return nil
 	}
+	if b.eventMap == nil {
+		return b.inferArity(evnt)
+	}
 	if eventType, found := (*b.eventMap)[evnt.topic]; found {
-		argTypes := typesOf(eventType)
-		expected := typesOf(evnt.data)
-		if !reflect.DeepEqual(argTypes, expected) {
+		if !matchesEventType(eventType, evnt.data) {
 			return fmt.Errorf("Message data mismatch")
 		}
 		return nil
 	}
-	return fmt.Errorf("No such topic, %q", evnt.topic)
+	return b.unknownTopic(evnt.topic)
+}
+
+func (b *bus) produceEvent(evnt event) (data []interface{}, err error) {
+	defer func() {
+		if x := recover(); x != nil {
+			err = fmt.Errorf("Failed to produce event data: %v", x)
+		}
+	}()
+	return evnt.produce(), nil
+}
+
+func (b *bus) verifyTopic(topic string) error {
+	if _, verified := b.verifiers[topic]; verified {
+		return nil
+	}
+	if b.eventMap == nil {
+		return nil
+	}
+	if _, found := (*b.eventMap)[topic]; found {
+		return nil
+	}
+	return b.unknownTopic(topic)
 }
 
 func (b *bus) broadcast(evnt event) error {
+	if evnt.produce != nil {
+		if !b.hasListeners(evnt.topic) {
+			return b.verifyTopic(evnt.topic)
+		}
+		data, err := b.produceEvent(evnt)
+		if err != nil {
+			return err
+		}
+		evnt.data = data
+	}
 	if err := b.verifyEvent(evnt); err != nil {
 		return err
 	}
-	if listeners, exists := b.topicListeners[evnt.topic]; exists {
+	if err := b.verifyPayloadSize(evnt); err != nil {
+		return err
+	}
+	if queue, ordered := b.timeOrdered[evnt.topic]; ordered {
+		b.holdUntilEffective(queue, evnt)
+		return nil
+	}
+	b.publish(evnt)
+	return nil
+}
+
+// publish delivers a verified event to all matching listeners.
+func (b *bus) publish(evnt event) {
+	b.countEvent(evnt.topic)
+	b.touchQuietGates(evnt.topic)
+	b.sequence++
+	evnt.seq = b.sequence
+	delivered := b.deliverEvent(evnt.topic, &evnt)
+	for pattern := range b.patterns {
+		if matchesPattern(pattern, evnt.topic) {
+			delivered += b.deliverEvent(pattern, &evnt)
+		}
+	}
+	b.recordReplay(evnt)
+	b.signalBackpressure(evnt.topic)
+	if delivered == 0 && b.deadLetter != nil {
+		b.deadLetter(evnt.topic, evnt.data)
+	}
+	b.emit(EventPosted, evnt.topic, delivered)
+}
+
+// deliverEvent hands an event to the listeners registered with
+// the given topic or pattern.
+// Returns the number of listeners the event was handed to, or held
+// for later delivery by.
+func (b *bus) deliverEvent(key string, evnt *event) int {
+	delivered := 0
+	held := 0
+	if listeners, exists := b.topicListeners[key]; exists {
+		// Listeners are only removed on the dispatcher goroutine, and the
+		// kept listeners are collected in a new slice, so listeners removed
+		// while delivering still get this event, and are removed before
+		// the next one.
 		keepList := []Listener{}
+		handoffs := make([]handoff, 0, len(listeners))
 		for _, l := range listeners {
-			l.channel <- evnt.data
+			if l.match != nil && !l.match(evnt) {
+				keepList = append(keepList, l)
+				continue
+			}
+			if l.hold != nil && l.hold(evnt) {
+				// Held for later delivery, but taken by the listener
+				held++
+				keepList = append(keepList, l)
+				continue
+			}
+			args := listenerArguments(l, evnt)
+			if evnt.tracker != nil {
+				args = evnt.tracker.track(args)
+			}
+			handoffs = append(handoffs, handoff{l, args})
 			if !l.once {
 				keepList = append(keepList, l)
-			} else {
-				close(l.channel)
 			}
 		}
-		b.topicListeners[evnt.topic] = keepList
+		slow := b.handOver(handoffs)
+		for _, h := range handoffs {
+			if h.listener.once {
+				b.dropListener(h.listener, true)
+			}
+		}
+		delivered = len(handoffs) - len(slow) + held
+		b.setListeners(key, keepList)
+		b.removeSlowListeners(slow)
+	}
+	return delivered
+}
+
+// listenerArguments returns the event arguments, prefixed by the
+// extra arguments expected by the listener.
+func listenerArguments(l Listener, evnt *event) []interface{} {
+	args := evnt.data
+	if l.wildcard {
+		args = append([]interface{}{evnt.topic}, args...)
+	}
+	if l.withID {
+		if evnt.id == "" {
+			evnt.id = newEventID()
+		}
+		args = append([]interface{}{evnt.id}, args...)
+	}
+	if l.withSeq {
+		args = append([]interface{}{evnt.seq}, args...)
+	}
+	if l.withHeaders {
+		args = append([]interface{}{evnt.headers}, args...)
+	}
+	return args
+}
+
+func newEventID() string {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(id)
+}
+
+func (b *bus) handleRequest(request busRequest) {
+	defer func() {
+		b.processed++
+		b.flushSyncDeliveries()
+	}()
+	switch request.request {
+	case addListenerReq:
+		request.errors <- b.addListener(request.listener)
+	case removeListenerReq:
+		b.removeListener(request.listener, false)
+	case drainListenerReq:
+		b.removeListener(request.listener, true)
+		request.errors <- nil
+	case sendEventReq:
+		b.countQueueWait(request.event.topic, time.Since(request.enqueued))
+		err := b.sendEvent(request.event)
+		if request.errors != nil {
+			request.errors <- err
+		} else if err != nil && b.errorHandler != nil {
+			callErrorHandler(b.errorHandler, request.event.topic, err)
+		}
+	case queryReq:
+		request.query()
+		request.errors <- nil
+	case waitListenersReq:
+		b.addWaiter(request.event.topic, listenerWaiter{
+			count:  request.count,
+			errors: request.errors,
+		})
 	}
-	return nil
 }
 
 // NewBus creates a new event bus.
@@ -290,25 +1874,68 @@ func (b *bus) broadcast(evnt event) error {
 // Specifying an event map makes listener registration and event
 // posting fail as early as possible.
 func NewBus(options ...Option) Bus {
-	b := &bus{queueLength: 10}
+	b := &bus{
+		queueLength: 10,
+		codec:       JSONCodec{},
+	}
 
+	b.options = options
 	for _, o := range options {
 		o(b)
 	}
 
 	b.requests = make(chan busRequest, b.queueLength)
+	b.control = make(chan busRequest, b.queueLength)
+	b.closed = make(chan struct{})
+	b.done = make(chan struct{})
+	if b.maxInFlight > 0 {
+		b.inFlight = make(chan struct{}, b.maxInFlight)
+	}
+	b.topicSlots = make(map[string]chan struct{})
+	for topic, n := range b.topicConcurrency {
+		if n > 0 {
+			b.topicSlots[topic] = make(chan struct{}, n)
+		}
+	}
 	b.topicListeners = make(map[string][]Listener)
+	b.patterns = make(map[string]bool)
+	b.pendingAdds = make(map[uint64]bool)
+	b.groups = make(map[string]*listenerGroup)
+	b.waiters = make(map[string][]listenerWaiter)
+	b.rates = make(map[string]*rateCounter)
+	b.counters = make(map[string]*deliveryCounters)
+	b.brokenTopics = make(map[string]error)
 
 	go func(b *bus) {
+		var subscriptionsDone <-chan struct{}
+		if b.subscriptionContext != nil {
+			subscriptionsDone = b.subscriptionContext.Done()
+		}
+
 		for {
-			request := <-b.requests
-			switch request.request {
-			case addListenerReq:
-				request.errors <- b.addListener(request.listener)
-			case removeListenerReq:
-				b.removeListener(request.listener)
-			case sendEventReq:
-				request.errors <- b.broadcast(request.event)
+			// Control requests take priority over everything else
+			select {
+			case request := <-b.control:
+				b.handleRequest(request)
+				continue
+			default:
+			}
+			select {
+			case request := <-b.control:
+				b.handleRequest(request)
+			case request := <-b.requests:
+				b.handleRequest(request)
+			case <-subscriptionsDone:
+				b.removeAllListeners()
+				subscriptionsDone = nil
+			case <-b.closed:
+				if atomic.LoadInt32(&b.draining) != 0 {
+					b.drainRequests()
+				}
+				b.notifyClose()
+				b.closeAllListeners()
+				close(b.done)
+				return
 			}
 		}
 	}(b)
@@ -1,9 +1,15 @@
 package eventually_test
 
 import (
+	"context"
 	"fmt"
 	events "github.com/erkkah/eventually"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestOnce(t *testing.T) {
@@ -55,6 +61,689 @@ func TestUnsubscribe(t *testing.T) {
 	}
 }
 
+func TestUnsubscribeKeepsOtherListeners(t *testing.T) {
+	b := events.NewBus()
+
+	removed, _ := b.On("ping", func() {
+		t.Error("Unsubscribed listener called")
+	})
+	done := make(chan bool)
+	b.On("ping", func() {
+		done <- true
+	})
+
+	b.Unsubscribe("ping", removed)
+	b.Post("ping")
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Remaining listener was not called")
+	}
+}
+
+func TestUnsubscribeFunc(t *testing.T) {
+	b := events.NewBus()
+
+	counted := make(chan bool, 2)
+	counter := func() {
+		counted <- true
+	}
+	b.On("ping", counter)
+	b.On("ping", counter)
+	done := make(chan bool)
+	b.On("ping", func() {
+		done <- true
+	})
+
+	b.UnsubscribeFunc("ping", counter)
+	if count := b.SubscriberCount("ping"); count != 2 {
+		t.Fatalf("Expected one listener to be removed, %d left", count)
+	}
+
+	b.UnsubscribeFunc("ping", func() {})
+	b.UnsubscribeFunc("pong", counter)
+	if count := b.SubscriberCount("ping"); count != 2 {
+		t.Fatalf("Expected no listeners to be removed, %d left", count)
+	}
+
+	b.Post("ping")
+	<-done
+	if err := b.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Failed to shut down: %v", err)
+	}
+	if n := len(counted); n != 1 {
+		t.Fatalf("Expected one remaining listener call, got %d", n)
+	}
+}
+
+func TestOnMany(t *testing.T) {
+	b := events.NewBus(events.WithEventMap(events.EventMap{
+		"user.created": {"name"},
+		"user.deleted": {"name"},
+		"user.aged":    {42},
+	}))
+
+	received := make(chan string, 2)
+	listeners, err := b.OnMany([]string{"user.created", "user.deleted"}, func(name string) {
+		received <- name
+	})
+	if err != nil {
+		t.Fatalf("Failed to register callbacks: %v", err)
+	}
+	if len(listeners) != 2 || listeners[1].Topic() != "user.deleted" {
+		t.Fatalf("Unexpected listeners: %v", listeners)
+	}
+	b.Post("user.created", "fred")
+	b.Post("user.deleted", "barney")
+	names := map[string]bool{<-received: true, <-received: true}
+	if !names["fred"] || !names["barney"] {
+		t.Fatalf("Unexpected names %v", names)
+	}
+
+	_, err = b.OnMany([]string{"user.created", "user.aged"}, func(name string) {})
+	if err == nil {
+		t.Fatal("Registering on a mismatching topic should fail")
+	}
+	if count := b.SubscriberCount("user.created"); count != 1 {
+		t.Fatalf("Expected registered listeners to be rolled back, found %d", count)
+	}
+}
+
+func TestWaitForListeners(t *testing.T) {
+	b := events.NewBus()
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		b.On("ping", func() {})
+		b.On("ping", func() {})
+	}()
+
+	if err := b.WaitForListeners("ping", 2, time.Second); err != nil {
+		t.Fatalf("Failed waiting for listeners: %v", err)
+	}
+
+	if err := b.WaitForListeners("ping", 3, 20*time.Millisecond); err == nil {
+		t.Fatal("Waiting for missing listeners should time out")
+	}
+}
+
+func TestCorrelationID(t *testing.T) {
+	b := events.NewBus()
+
+	type received struct {
+		id   string
+		data []interface{}
+	}
+	done := make(chan received)
+
+	_, err := b.OnWithID("ping", func(id string, data ...interface{}) {
+		done <- received{id, data}
+	})
+	if err != nil {
+		t.Fatalf("Failed to register callback: %v", err)
+	}
+
+	b.PostWithID("request-42", "ping", "foo", 12)
+	result := <-done
+	if result.id != "request-42" {
+		t.Fatalf("Expected correlation id %q, got %q", "request-42", result.id)
+	}
+	if len(result.data) != 2 || result.data[0] != "foo" || result.data[1] != 12 {
+		t.Fatalf("Unexpected event data: %v", result.data)
+	}
+
+	b.Post("ping")
+	result = <-done
+	if result.id == "" {
+		t.Fatal("Expected generated correlation id")
+	}
+}
+
+func TestSubscribeChan(t *testing.T) {
+	b := events.NewBus()
+
+	ch := make(chan []interface{}, 2)
+	listener, err := b.SubscribeChan("ping", ch)
+	if err != nil {
+		t.Fatalf("Failed to subscribe channel: %v", err)
+	}
+
+	b.Post("ping", 1)
+	b.Post("ping", 2)
+
+	for i := 1; i <= 2; i++ {
+		data := <-ch
+		if len(data) != 1 || data[0] != i {
+			t.Fatalf("Expected event %d, got %v", i, data)
+		}
+	}
+
+	b.Unsubscribe("ping", listener)
+	b.Post("ping", 3)
+
+	select {
+	case data := <-ch:
+		t.Fatalf("Unexpected event after unsubscribe: %v", data)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	b := events.NewBus()
+
+	ch, listener, err := b.Subscribe("ping", 2)
+	if err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+
+	b.Post("ping", 1)
+	b.Post("ping", 2)
+	b.Unsubscribe("ping", listener)
+
+	received := 0
+	for data := range ch {
+		received++
+		if len(data) != 1 || data[0] != received {
+			t.Fatalf("Expected event %d, got %v", received, data)
+		}
+	}
+	if received != 2 {
+		t.Fatalf("Expected 2 events before the channel was closed, got %d", received)
+	}
+	if _, ok := <-ch; ok {
+		t.Fatal("Expected channel to be closed")
+	}
+}
+
+// testSubscribeCloses checks that Subscribe channels are closed both
+// when unsubscribing and when closing the bus.
+func testSubscribeCloses(t *testing.T, b events.Bus) {
+	ch, listener, err := b.Subscribe("ping", 2)
+	if err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	b.Post("ping", 1)
+	b.Post("ping", 2)
+	b.Unsubscribe("ping", listener)
+
+	received := 0
+	for range ch {
+		received++
+	}
+	if received != 2 {
+		t.Fatalf("Expected 2 events before the channel was closed, got %d", received)
+	}
+
+	ch, _, err = b.Subscribe("ping", 1)
+	if err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	b.Post("ping", 3)
+	b.Close()
+	received = 0
+	for range ch {
+		received++
+	}
+	if received != 1 {
+		t.Fatalf("Expected 1 event before the channel was closed, got %d", received)
+	}
+}
+
+func TestMaxInFlight(t *testing.T) {
+	b := events.NewBus(events.WithMaxInFlight(2))
+
+	const listeners = 10
+	var running, maxRunning int32
+	var delivered sync.WaitGroup
+	delivered.Add(listeners)
+
+	for i := 0; i < listeners; i++ {
+		b.On("work", func() {
+			defer delivered.Done()
+			current := atomic.AddInt32(&running, 1)
+			for {
+				max := atomic.LoadInt32(&maxRunning)
+				if current <= max || atomic.CompareAndSwapInt32(&maxRunning, max, current) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+		})
+	}
+
+	b.Post("work")
+	delivered.Wait()
+
+	if max := atomic.LoadInt32(&maxRunning); max > 2 {
+		t.Fatalf("Expected at most 2 concurrent callbacks, got %d", max)
+	}
+}
+
+func TestReentrantUnsubscribe(t *testing.T) {
+	b := events.NewBus()
+
+	var listenerB events.Listener
+	unsubscribed := make(chan bool)
+	receivedB := make(chan int, 10)
+
+	b.On("ping", func(msg int) {
+		b.Unsubscribe("ping", listenerB)
+		unsubscribed <- true
+	})
+	listenerB, _ = b.On("ping", func(msg int) {
+		receivedB <- msg
+	})
+
+	b.Post("ping", 1)
+	<-unsubscribed
+	if msg := <-receivedB; msg != 1 {
+		t.Fatalf("Expected in-progress event, got %v", msg)
+	}
+
+	b.Post("ping", 2)
+	select {
+	case msg := <-receivedB:
+		t.Fatalf("Unexpected event after unsubscribe: %v", msg)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestPostFunc(t *testing.T) {
+	b := events.NewBus()
+
+	produced := 0
+	produce := func() []interface{} {
+		produced++
+		return []interface{}{"expensive", produced}
+	}
+
+	if err := b.PostFunc("ping", produce); err != nil {
+		t.Fatalf("Failed to post event: %v", err)
+	}
+	if produced != 0 {
+		t.Fatal("Event data produced without listeners")
+	}
+
+	done := make(chan bool)
+	b.Once("ping", func(msg string, count int) {
+		if msg != "expensive" || count != 1 {
+			t.Errorf("Unexpected event: %v, %v", msg, count)
+		}
+		done <- true
+	})
+
+	if err := b.PostFunc("ping", produce); err != nil {
+		t.Fatalf("Failed to post event: %v", err)
+	}
+	<-done
+	if produced != 1 {
+		t.Fatalf("Expected event data to be produced once, got %d", produced)
+	}
+}
+
+func TestUnsubscribePriority(t *testing.T) {
+	b := events.NewBus(events.WithQueueLength(100))
+
+	// Signals each event the dispatcher starts handing out
+	handed := make(chan bool, 100)
+	b.On("flood", func() {
+		handed <- true
+	})
+	release := make(chan bool)
+	var received int32
+	listener, _ := b.On("flood", func() {
+		if atomic.AddInt32(&received, 1) == 1 {
+			<-release
+		}
+	})
+
+	// The first event blocks the listener, and the dispatcher gets
+	// stuck handing it the second one
+	b.PostNoWait("flood")
+	b.PostNoWait("flood")
+	<-handed
+	<-handed
+
+	for i := 0; i < 50; i++ {
+		b.PostNoWait("flood")
+	}
+	b.Unsubscribe("flood", listener)
+	close(release)
+
+	if err := b.Post("flood"); err != nil {
+		t.Fatalf("Failed to post: %v", err)
+	}
+	if err := b.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Failed to shut down: %v", err)
+	}
+	if n := atomic.LoadInt32(&received); n != 2 {
+		t.Fatalf("Expected listener to receive 2 events before unsubscribing, got %d", n)
+	}
+	if n := len(handed); n != 51 {
+		t.Fatalf("Expected remaining listener to receive 51 more events, got %d", n)
+	}
+}
+
+func TestUnsubscribeBeforeAsyncAdd(t *testing.T) {
+	b := events.NewBus()
+
+	started := make(chan bool)
+	release := make(chan bool)
+	b.On("slow", func() {
+		started <- true
+		<-release
+	})
+
+	// Keep the dispatcher busy, so that the add request stays queued
+	b.PostNoWait("slow")
+	<-started
+	b.PostNoWait("slow")
+
+	listener, result := b.OnAsync("ping", func() {
+		t.Error("Unsubscribed listener called")
+	})
+	b.Unsubscribe("ping", listener)
+	close(release)
+	<-started
+
+	if err := <-result; err != nil {
+		t.Fatalf("Failed to register listener: %v", err)
+	}
+	if count := b.SubscriberCount("ping"); count != 0 {
+		t.Fatalf("Expected listener to stay unsubscribed, found %d", count)
+	}
+	b.Post("ping")
+}
+
+func TestSubscriptionContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	b := events.NewBus(events.WithSubscriptionContext(ctx))
+
+	var subscriptions []<-chan []interface{}
+	for _, topic := range []string{"ping", "pong", "ping"} {
+		ch, _, err := b.Subscribe(topic, 10)
+		if err != nil {
+			t.Fatalf("Failed to subscribe: %v", err)
+		}
+		subscriptions = append(subscriptions, ch)
+	}
+
+	b.Post("ping")
+	cancel()
+
+	// Subscription channels are closed when their listeners are removed
+	received := 0
+	for _, ch := range subscriptions {
+		for range ch {
+			received++
+		}
+	}
+	if received != 2 {
+		t.Fatalf("Expected 2 events before cancellation, got %d", received)
+	}
+
+	b.Post("ping")
+	b.Post("pong")
+	if count := b.SubscriberCount("ping") + b.SubscriberCount("pong"); count != 0 {
+		t.Fatalf("Expected all listeners to be removed, found %d", count)
+	}
+}
+
+func TestMaxPayloadBytes(t *testing.T) {
+	b := events.NewBus(events.WithMaxPayloadBytes(100))
+
+	if err := b.Post("upload", "small"); err != nil {
+		t.Fatalf("Failed to post small payload: %v", err)
+	}
+
+	large := strings.Repeat("large", 100)
+	if err := b.Post("upload", large); err != events.ErrPayloadTooLarge {
+		t.Fatalf("Expected ErrPayloadTooLarge, got: %v", err)
+	}
+}
+
+func TestListenerContext(t *testing.T) {
+	b := events.NewBus()
+
+	started := make(chan bool)
+	cancelled := make(chan error)
+
+	listener, err := b.On("work", func(ctx context.Context, job int) {
+		started <- true
+		<-ctx.Done()
+		cancelled <- ctx.Err()
+	})
+	if err != nil {
+		t.Fatalf("Failed to register callback: %v", err)
+	}
+
+	b.Post("work", 1)
+	<-started
+	b.Unsubscribe("work", listener)
+
+	select {
+	case err := <-cancelled:
+		if err != context.Canceled {
+			t.Fatalf("Unexpected context error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Callback context was not cancelled")
+	}
+}
+
+func TestListenerContext_Close(t *testing.T) {
+	b := events.NewBus()
+
+	started := make(chan bool)
+	cancelled := make(chan error, 1)
+
+	_, err := b.On("work", func(ctx context.Context, job int) {
+		started <- true
+		<-ctx.Done()
+		cancelled <- ctx.Err()
+	})
+	if err != nil {
+		t.Fatalf("Failed to register callback: %v", err)
+	}
+
+	b.Post("work", 1)
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := b.Shutdown(ctx); err != nil {
+		t.Fatalf("Failed to shut down: %v", err)
+	}
+	if err := <-cancelled; err != context.Canceled {
+		t.Fatalf("Unexpected context error: %v", err)
+	}
+}
+
+func TestEventMap_ContextListener(t *testing.T) {
+	topics := events.EventMap{
+		"hello": {"string", 42},
+	}
+
+	b := events.NewBus(events.WithEventMap(topics))
+	_, err := b.On("hello", func(ctx context.Context, s string, i int) {})
+	if err != nil {
+		t.Fatalf("Failed to register callback: %v", err)
+	}
+}
+
+func TestSequenceNumbers(t *testing.T) {
+	b := events.NewBus()
+
+	type received struct {
+		seq   uint64
+		topic interface{}
+	}
+	done := make(chan received, 10)
+
+	_, err := b.OnSeq("*", func(seq uint64, data ...interface{}) {
+		done <- received{seq, data[0]}
+	})
+	if err != nil {
+		t.Fatalf("Failed to register callback: %v", err)
+	}
+
+	topics := []string{"a", "b", "a", "c"}
+	for _, topic := range topics {
+		b.Post(topic)
+	}
+
+	var last uint64
+	for _, topic := range topics {
+		result := <-done
+		if result.topic != topic {
+			t.Fatalf("Expected event for topic %q, got %q", topic, result.topic)
+		}
+		if result.seq <= last {
+			t.Fatalf("Sequence number %d not after %d", result.seq, last)
+		}
+		last = result.seq
+	}
+}
+
+func TestOnTagged(t *testing.T) {
+	b := events.NewBus()
+
+	received := make(chan string, 10)
+	b.OnTagged("ping", "handler", func() {
+		received <- "first"
+	})
+	b.OnTagged("ping", "handler", func() {
+		received <- "second"
+	})
+	b.OnTagged("ping", "other", func() {
+		received <- "other"
+	})
+
+	b.Post("ping")
+
+	got := map[string]bool{}
+	got[<-received] = true
+	got[<-received] = true
+	if !got["second"] || !got["other"] {
+		t.Fatalf("Expected replacing and other listener to fire, got %v", got)
+	}
+
+	select {
+	case name := <-received:
+		t.Fatalf("Unexpected event for %q listener", name)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestPostNoWait(t *testing.T) {
+	topics := events.EventMap{
+		"hello": {"string", 42},
+	}
+
+	b := events.NewBus(events.WithEventMap(topics))
+
+	failed := make(chan error)
+	b.OnError(func(topic string, err error) {
+		failed <- err
+	})
+
+	done := make(chan bool)
+	b.Once("hello", func(s string, i int) {
+		done <- true
+	})
+
+	b.PostNoWait("hello", "foo", 12)
+	<-done
+
+	b.PostNoWait("hello", "foo", "splat")
+	if err := <-failed; err == nil {
+		t.Fatal("Expected mistyped event error")
+	}
+
+	b.Close()
+	go b.PostNoWait("hello", "foo", 12)
+	if err := <-failed; err != events.ErrBusClosed {
+		t.Fatalf("Expected ErrBusClosed, got %v", err)
+	}
+}
+
+func BenchmarkPost(bench *testing.B) {
+	b := events.NewBus()
+	b.On("bench", func(i int) {})
+
+	bench.ReportAllocs()
+	bench.ResetTimer()
+	for i := 0; i < bench.N; i++ {
+		b.Post("bench", i)
+	}
+}
+
+func BenchmarkPostNoWait(bench *testing.B) {
+	b := events.NewBus()
+	b.On("bench", func(i int) {})
+
+	bench.ReportAllocs()
+	bench.ResetTimer()
+	for i := 0; i < bench.N; i++ {
+		b.PostNoWait("bench", i)
+	}
+}
+
+func TestHeaders(t *testing.T) {
+	b := events.NewBus()
+
+	type received struct {
+		headers map[string]string
+		data    []interface{}
+	}
+	done := make(chan received)
+
+	_, err := b.OnWithHeaders("ping", func(headers map[string]string, data ...interface{}) {
+		done <- received{headers, data}
+	})
+	if err != nil {
+		t.Fatalf("Failed to register callback: %v", err)
+	}
+
+	headers := map[string]string{"tenant": "A", "trace": "1234"}
+	b.PostWithHeaders("ping", headers, "foo", 12)
+
+	result := <-done
+	if !reflect.DeepEqual(result.headers, headers) {
+		t.Fatalf("Expected headers %v, got %v", headers, result.headers)
+	}
+	if len(result.data) != 2 || result.data[0] != "foo" || result.data[1] != 12 {
+		t.Fatalf("Unexpected event data: %v", result.data)
+	}
+}
+
+func TestOnHeaderMatch(t *testing.T) {
+	b := events.NewBus()
+
+	received := make(chan string, 10)
+	_, err := b.OnHeaderMatch("order", map[string]string{"tenant": "A"}, func(id string) {
+		received <- id
+	})
+	if err != nil {
+		t.Fatalf("Failed to register callback: %v", err)
+	}
+
+	b.PostWithHeaders("order", map[string]string{"tenant": "B"}, "order-b")
+	b.Post("order", "order-none")
+	b.PostWithHeaders("order", map[string]string{"tenant": "A", "trace": "1"}, "order-a")
+
+	if id := <-received; id != "order-a" {
+		t.Fatalf("Expected only the tenant A event, got %q", id)
+	}
+	select {
+	case id := <-received:
+		t.Fatalf("Unexpected event %q", id)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
 func TestEventMap_OKListenerAndEvent(t *testing.T) {
 	topics := events.EventMap{
 		"hello": {"string", 42},
@@ -77,6 +766,73 @@ func TestEventMap_OKListenerAndEvent(t *testing.T) {
 	}
 }
 
+func TestEventMap_CorrelationID(t *testing.T) {
+	topics := events.EventMap{
+		"hello": {"string", 42},
+	}
+
+	b := events.NewBus(events.WithEventMap(topics))
+
+	done := make(chan string)
+	_, err := b.OnWithID("hello", func(id string, data ...interface{}) {
+		done <- id
+	})
+	if err != nil {
+		t.Fatalf("Failed to register callback: %v", err)
+	}
+
+	err = b.PostWithID("abc", "hello", "foo", 12)
+	if err != nil {
+		t.Fatalf("Failed to post event: %v", err)
+	}
+	if id := <-done; id != "abc" {
+		t.Fatalf("Expected correlation id %q, got %q", "abc", id)
+	}
+}
+
+func TestEventMap_PostMulti(t *testing.T) {
+	topics := events.EventMap{
+		"created": {"name"},
+		"updated": {"name"},
+		"deleted": {42},
+	}
+
+	b := events.NewBus(events.WithEventMap(topics))
+
+	received := make(chan string, 10)
+	for topic := range topics {
+		topic := topic
+		if topic == "deleted" {
+			b.On(topic, func(i int) {
+				received <- topic
+			})
+		} else {
+			b.On(topic, func(name string) {
+				received <- topic
+			})
+		}
+	}
+
+	if err := b.PostMulti([]string{"created", "updated"}, "fred"); err != nil {
+		t.Fatalf("Failed to post event: %v", err)
+	}
+	got := map[string]bool{}
+	got[<-received] = true
+	got[<-received] = true
+	if !got["created"] || !got["updated"] {
+		t.Fatalf("Expected events on both topics, got %v", got)
+	}
+
+	if err := b.PostMulti([]string{"created", "deleted"}, "fred"); err == nil {
+		t.Fatal("Posting mistyped event to any topic should fail")
+	}
+	select {
+	case topic := <-received:
+		t.Fatalf("Unexpected %q event", topic)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
 func TestEventMap_BadListener(t *testing.T) {
 	topics := events.EventMap{
 		"hello": {"string", 42},
@@ -128,6 +884,67 @@ func TestEventMap_UnknownTopic(t *testing.T) {
 	}
 }
 
+func TestEventMap_TopicSuggestions(t *testing.T) {
+	topics := events.EventMap{
+		"hello":   {"string", 42},
+		"goodbye": {"string"},
+	}
+
+	b := events.NewBus(events.WithEventMap(topics), events.WithTopicSuggestions())
+
+	err := b.Post("hällo", "foo", 12)
+	if err == nil {
+		t.Fatal("Posting to unknown topic should fail")
+	}
+	if !strings.Contains(err.Error(), `did you mean "hello"?`) {
+		t.Fatalf("Expected topic suggestion, got: %v", err)
+	}
+
+	_, err = b.Once("godbye", func(s string) {})
+	if err == nil || !strings.Contains(err.Error(), `did you mean "goodbye"?`) {
+		t.Fatalf("Expected topic suggestion, got: %v", err)
+	}
+}
+
+func TestTopicVerifier(t *testing.T) {
+	topics := events.EventMap{
+		"hello": {"string", 42},
+	}
+
+	b := events.NewBus(
+		events.WithEventMap(topics),
+		events.WithTopicVerifier("show", func(data []interface{}) error {
+			if len(data) != 1 {
+				return fmt.Errorf("Expected one argument")
+			}
+			if _, ok := data[0].(fmt.Stringer); !ok {
+				return fmt.Errorf("Expected a fmt.Stringer")
+			}
+			return nil
+		}),
+	)
+
+	done := make(chan string)
+	_, err := b.Once("show", func(s fmt.Stringer) {
+		done <- s.String()
+	})
+	if err != nil {
+		t.Fatalf("Failed to register callback: %v", err)
+	}
+
+	if err := b.Post("show", 42); err == nil {
+		t.Fatal("Posting a non-Stringer should fail")
+	}
+
+	if err := b.Post("show", time.Second); err != nil {
+		t.Fatalf("Failed to post Stringer: %v", err)
+	}
+
+	if s := <-done; s != "1s" {
+		t.Fatalf("Unexpected event: %v", s)
+	}
+}
+
 func TestOnError(t *testing.T) {
 	b := events.NewBus()
 
@@ -146,6 +963,64 @@ func TestOnError(t *testing.T) {
 	<-crashed
 }
 
+func TestReturnedErrorWithoutHandler(t *testing.T) {
+	b := events.NewBus()
+
+	received := make(chan int, 2)
+	b.On("save", func(n int) error {
+		received <- n
+		return fmt.Errorf("Cannot save")
+	})
+
+	b.Post("save", 1)
+	b.Post("save", 2)
+
+	if n := <-received; n != 1 {
+		t.Fatalf("Unexpected event: %v", n)
+	}
+	if n := <-received; n != 2 {
+		t.Fatalf("Unexpected event: %v", n)
+	}
+}
+
+func TestOnAsync(t *testing.T) {
+	b := events.NewBus()
+
+	release := make(chan bool)
+	b.On("slow", func() {
+		<-release
+	})
+
+	// Keep the bus busy delivering to the slow listener
+	go func() {
+		b.Post("slow")
+		b.Post("slow")
+	}()
+
+	const listeners = 200
+	var delivered sync.WaitGroup
+	delivered.Add(listeners)
+
+	results := []<-chan error{}
+	for i := 0; i < listeners; i++ {
+		_, result := b.OnAsync("ping", func() {
+			delivered.Done()
+		})
+		results = append(results, result)
+	}
+
+	close(release)
+
+	for _, result := range results {
+		if err := <-result; err != nil {
+			t.Fatalf("Failed to register callback: %v", err)
+		}
+	}
+
+	b.Post("ping")
+	delivered.Wait()
+}
+
 func Example() {
 	foo := func(msg string) {
 		fmt.Printf("foo: %v\n", msg)
@@ -207,3 +1082,368 @@ func ExampleEventMap() {
 	// Hello event expects other arguments
 	// Name: "Fred", age: 9
 }
+
+type recordingHandler struct {
+	events chan string
+}
+
+func (h recordingHandler) Handle(topic string, data ...interface{}) {
+	h.events <- fmt.Sprint(topic, data)
+}
+
+func TestEventHandler(t *testing.T) {
+	b := events.NewBus()
+
+	handler := recordingHandler{make(chan string, 1)}
+	_, err := b.On("handled", handler)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b.Post("handled", "a", 1)
+
+	if result := <-handler.events; result != "handled[a 1]" {
+		t.Fatalf("Unexpected handler call: %q", result)
+	}
+}
+
+func TestClose(t *testing.T) {
+	b := events.NewBus()
+
+	release := make(chan bool)
+	delivered := make(chan int, 2)
+	b.On("work", func(n int) {
+		<-release
+		delivered <- n
+	})
+
+	b.Post("work", 1)
+	go b.Post("work", 2)
+	time.Sleep(5 * time.Millisecond)
+
+	closed := make(chan bool)
+	go func() {
+		b.Close()
+		closed <- true
+	}()
+	time.Sleep(5 * time.Millisecond)
+	close(release)
+	<-closed
+
+	if err := b.Post("work", 3); err != events.ErrBusClosed {
+		t.Fatalf("Expected ErrBusClosed when posting, got %v", err)
+	}
+	if _, err := b.On("work", func(int) {}); err != events.ErrBusClosed {
+		t.Fatalf("Expected ErrBusClosed when subscribing, got %v", err)
+	}
+	if err := b.Close(); err != nil {
+		t.Fatalf("Expected closing twice to succeed, got %v", err)
+	}
+
+	if first := <-delivered; first != 1 {
+		t.Fatalf("Expected first event to be delivered, got %v", first)
+	}
+
+	for i := 0; i < 100 && b.Diagnostics().ListenerGoroutines > 0; i++ {
+		time.Sleep(time.Millisecond)
+	}
+	if running := b.Diagnostics().ListenerGoroutines; running != 0 {
+		t.Fatalf("Expected listener goroutines to exit, %d still running", running)
+	}
+}
+
+func TestWithErrorHandler(t *testing.T) {
+	reported := make(chan string, 2)
+	b := events.NewBus(events.WithErrorHandler(func(topic string, err error) {
+		reported <- topic
+		panic("Failing handler")
+	}))
+
+	b.On("crash", func() {
+		panic("Crash")
+	})
+
+	b.Post("crash")
+	b.Post("crash")
+
+	for i := 0; i < 2; i++ {
+		if topic := <-reported; topic != "crash" {
+			t.Fatalf("Unexpected topic reported: %q", topic)
+		}
+	}
+}
+
+func TestWithDeadLetter(t *testing.T) {
+	var dead []string
+	b := events.NewBus(events.WithDeadLetter(func(topic string, data []interface{}) {
+		dead = append(dead, fmt.Sprint(topic, data))
+	}))
+
+	b.On("ping", func(i int) {})
+	b.On("user.*", func(topic string, data ...interface{}) {})
+
+	b.Post("ping", 1)
+	b.Post("pnig", 2)
+	b.Post("user.created", 3)
+	b.Post("pong", 4)
+	b.Close()
+
+	if expected := []string{"pnig[2]", "pong[4]"}; !reflect.DeepEqual(dead, expected) {
+		t.Fatalf("Expected dead letters %v, got %v", expected, dead)
+	}
+}
+
+func TestWithDeadLetter_Held(t *testing.T) {
+	var dead []string
+	b := events.NewBus(events.WithDeadLetter(func(topic string, data []interface{}) {
+		dead = append(dead, fmt.Sprint(topic, data))
+	}))
+
+	reduced := make(chan int, 1)
+	b.OnReduced("sum", time.Millisecond, func(acc, next []interface{}) []interface{} {
+		return []interface{}{acc[0].(int) + next[0].(int)}
+	}, func(n int) {
+		reduced <- n
+	})
+	b.OnAfterQuiet("save", "typing", time.Hour, func() {})
+	b.On("typing", func() {})
+
+	b.Post("typing")
+	b.Post("save")
+	b.Post("sum", 1)
+	b.Post("sum", 2)
+	if n := <-reduced; n != 3 {
+		t.Fatalf("Expected reduced sum 3, got %d", n)
+	}
+	b.Close()
+
+	if len(dead) != 0 {
+		t.Fatalf("Expected no dead letters for held events, got %v", dead)
+	}
+}
+
+func TestOnFiltered(t *testing.T) {
+	b := events.NewBus()
+
+	received := make(chan int, 5)
+	_, err := b.OnFiltered("count", func(data []interface{}) bool {
+		return data[0].(int)%2 == 0
+	}, func(n int) {
+		received <- n
+	})
+	if err != nil {
+		t.Fatalf("Failed to register callback: %v", err)
+	}
+	unfiltered := make(chan int, 5)
+	b.OnFiltered("count", nil, func(n int) {
+		unfiltered <- n
+	})
+
+	for i := 1; i <= 5; i++ {
+		b.Post("count", i)
+	}
+
+	for _, expected := range []int{2, 4} {
+		if n := <-received; n != expected {
+			t.Fatalf("Expected %d, got %d", expected, n)
+		}
+	}
+	for expected := 1; expected <= 5; expected++ {
+		if n := <-unfiltered; n != expected {
+			t.Fatalf("Expected unfiltered %d, got %d", expected, n)
+		}
+	}
+	if len(received) != 0 {
+		t.Fatalf("Unexpected extra events: %d", len(received))
+	}
+}
+
+func TestOnSkipFirst(t *testing.T) {
+	b := events.NewBus()
+
+	received := make(chan int, 5)
+	_, err := b.OnSkipFirst("count", 2, func(n int) {
+		received <- n
+	})
+	if err != nil {
+		t.Fatalf("Failed to register callback: %v", err)
+	}
+
+	for i := 1; i <= 5; i++ {
+		b.Post("count", i)
+	}
+
+	for expected := 3; expected <= 5; expected++ {
+		if n := <-received; n != expected {
+			t.Fatalf("Expected %d, got %d", expected, n)
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	if len(received) != 0 {
+		t.Fatalf("Unexpected extra events: %d", len(received))
+	}
+}
+
+func TestWaitFor(t *testing.T) {
+	b := events.NewBus()
+
+	go func() {
+		b.WaitForListeners("ready", 1, time.Second)
+		b.Post("ready", "go", 1)
+	}()
+
+	data, err := events.WaitFor(b, "ready", time.Second)
+	if err != nil {
+		t.Fatalf("Failed waiting for event: %v", err)
+	}
+	if !reflect.DeepEqual(data, []interface{}{"go", 1}) {
+		t.Fatalf("Unexpected event data: %v", data)
+	}
+
+	_, err = events.WaitFor(b, "never", 10*time.Millisecond)
+	if err == nil {
+		t.Fatal("Expected timeout waiting for event")
+	}
+	if len(b.Listeners()) != 0 {
+		t.Fatalf("Expected timed out listener to be removed, got %v", b.Listeners())
+	}
+}
+
+func TestErrorReturningCallbacks(t *testing.T) {
+	failed := make(chan error, 1)
+	b := events.NewBus(
+		events.WithEventMap(events.EventMap{"save": {"name"}}),
+		events.WithErrorHandler(func(topic string, err error) {
+			failed <- err
+		}),
+	)
+
+	saved := make(chan string, 1)
+	if _, err := b.On("save", func(name string) {
+		saved <- name
+	}); err != nil {
+		t.Fatalf("Failed to register callback without result: %v", err)
+	}
+	if _, err := b.On("save", func(name string) error {
+		return fmt.Errorf("Cannot save %s", name)
+	}); err != nil {
+		t.Fatalf("Failed to register error returning callback: %v", err)
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("Registering callback with non-error result should panic")
+			}
+		}()
+		b.On("save", func(name string) int { return 0 })
+	}()
+
+	b.Post("save", "doc")
+
+	if name := <-saved; name != "doc" {
+		t.Fatalf("Unexpected event: %q", name)
+	}
+	if err := <-failed; err.Error() != "Cannot save doc" {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestErrorReturningCallbacks_Sync(t *testing.T) {
+	b := events.NewBus(events.WithSyncDispatch())
+
+	b.On("save", func(name string) error {
+		return fmt.Errorf("Cannot save %s", name)
+	})
+	b.On("save", func(name string) error {
+		return nil
+	})
+
+	if err := b.Post("save", "doc"); err == nil || err.Error() != "Cannot save doc" {
+		t.Fatalf("Expected callback error from Post, got %v", err)
+	}
+}
+
+func TestUnsubscribeDrain(t *testing.T) {
+	b := events.NewBus(events.WithTopicBuffer("work", 5))
+
+	started := make(chan bool, 5)
+	release := make(chan bool)
+	var processed int32
+	listener, _ := b.On("work", func() {
+		started <- true
+		<-release
+		atomic.AddInt32(&processed, 1)
+	})
+
+	b.Post("work")
+	<-started
+	for i := 0; i < 4; i++ {
+		b.Post("work")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := b.UnsubscribeDrain(listener, ctx); err != context.DeadlineExceeded {
+		t.Fatalf("Expected drain to time out, got %v", err)
+	}
+
+	close(release)
+	if err := b.UnsubscribeDrain(listener, context.Background()); err != nil {
+		t.Fatalf("Failed to drain listener: %v", err)
+	}
+	if n := atomic.LoadInt32(&processed); n != 5 {
+		t.Fatalf("Expected all 5 events to be processed, got %d", n)
+	}
+}
+
+func TestUnsubscribeDrain_Modes(t *testing.T) {
+	modes := []struct {
+		name    string
+		bus     events.Bus
+		grouped bool
+		posts   int
+	}{
+		{"executor", events.NewBus(events.WithExecutor(&countingExecutor{})), false, 3},
+		{"sync", events.NewBus(events.WithSyncDispatch()), false, 3},
+		{"grouped", events.NewBus(), true, 1},
+	}
+
+	for _, mode := range modes {
+		t.Run(mode.name, func(t *testing.T) {
+			b := mode.bus
+			started := make(chan bool, mode.posts)
+			release := make(chan bool)
+			var processed int32
+			callback := func() {
+				started <- true
+				<-release
+				atomic.AddInt32(&processed, 1)
+			}
+			var listener events.Listener
+			if mode.grouped {
+				listener, _ = b.OnGrouped("work", "group", callback)
+			} else {
+				listener, _ = b.On("work", callback)
+			}
+
+			for i := 0; i < mode.posts; i++ {
+				go b.Post("work")
+				<-started
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+			if err := b.UnsubscribeDrain(listener, ctx); err != context.DeadlineExceeded {
+				t.Fatalf("Expected drain to time out, got %v", err)
+			}
+
+			close(release)
+			if err := b.UnsubscribeDrain(listener, context.Background()); err != nil {
+				t.Fatalf("Failed to drain listener: %v", err)
+			}
+			if n := atomic.LoadInt32(&processed); n != int32(mode.posts) {
+				t.Fatalf("Expected all %d events to be processed, got %d", mode.posts, n)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package eventually
+
+import "sync/atomic"
+
+// Executor runs listener deliveries.
+type Executor interface {
+	// Submit runs a task, typically on another goroutine.
+	Submit(task func())
+}
+
+// WithExecutor makes the bus submit each listener delivery to an
+// executor, instead of delivering events on a goroutine per listener.
+// Deliveries are submitted in the order events are posted, but the order
+// in which they run is up to the executor.
+// Grouped listeners still share a single goroutine per group.
+func WithExecutor(executor Executor) Option {
+	return func(b *bus) {
+		b.executor = executor
+	}
+}
+
+// dispatch hands an event to a listener, using the executor if there is one.
+// Returns false if the listener was too slow to take the event.
+func (b *bus) dispatch(l Listener, args []interface{}) bool {
+	atomic.AddInt32(&b.outstanding, 1)
+	l.outstanding.add()
+	if b.syncDispatch {
+		b.syncDeliveries = append(b.syncDeliveries, handoff{l, args})
+		return true
+	}
+	if b.executor != nil && l.group == nil {
+		b.executor.Submit(func() {
+			b.deliver(l, args)
+		})
+		return true
+	}
+	return b.send(l, args)
+}
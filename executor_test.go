@@ -0,0 +1,46 @@
+package eventually_test
+
+import (
+	events "github.com/erkkah/eventually"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+type countingExecutor struct {
+	submitted int32
+}
+
+func (e *countingExecutor) Submit(task func()) {
+	atomic.AddInt32(&e.submitted, 1)
+	go task()
+}
+
+func TestWithExecutor(t *testing.T) {
+	executor := &countingExecutor{}
+	b := events.NewBus(events.WithExecutor(executor))
+
+	var delivered sync.WaitGroup
+	delivered.Add(6)
+	for i := 0; i < 2; i++ {
+		b.On("ping", func(int) {
+			delivered.Done()
+		})
+	}
+
+	for i := 0; i < 3; i++ {
+		b.Post("ping", i)
+	}
+	delivered.Wait()
+
+	if submitted := atomic.LoadInt32(&executor.submitted); submitted != 6 {
+		t.Fatalf("Expected 6 submitted deliveries, got %d", submitted)
+	}
+	if running := b.Diagnostics().ListenerGoroutines; running != 0 {
+		t.Fatalf("Expected no listener goroutines, got %d", running)
+	}
+}
+
+func TestWithExecutor_Subscribe(t *testing.T) {
+	testSubscribeCloses(t, events.NewBus(events.WithExecutor(&countingExecutor{})))
+}
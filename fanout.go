@@ -0,0 +1,71 @@
+package eventually
+
+import "sync"
+
+// WithFanOut makes the dispatcher hand events for topics with more than
+// n matching listeners to helper goroutines, each serving up to n
+// listeners, instead of handing the event to each listener in turn.
+// The dispatcher still waits until every listener has been handed the
+// event, so per-listener ordering is kept, but a few slow listeners no
+// longer hold up the rest.
+//
+// Grouped listeners, and all listeners on buses using an executor or
+// synchronous dispatch, are handed events by the dispatcher itself.
+func WithFanOut(n int) Option {
+	return func(b *bus) {
+		b.fanOut = n
+	}
+}
+
+// handOver dispatches events to listeners, spreading the work over helper
+// goroutines when fan-out is enabled and there are enough listeners.
+// Returns the listeners that were too slow to take their event.
+// Called on the dispatcher goroutine.
+func (b *bus) handOver(handoffs []handoff) (slow []Listener) {
+	if b.fanOut <= 0 || len(handoffs) <= b.fanOut || b.executor != nil || b.syncDispatch {
+		for _, h := range handoffs {
+			if !b.dispatch(h.listener, h.args) {
+				slow = append(slow, h.listener)
+			}
+		}
+		return
+	}
+
+	// Grouped listeners share a queue, so keep their relative order
+	// by handing them their events here.
+	parallel := make([]handoff, 0, len(handoffs))
+	for _, h := range handoffs {
+		if h.listener.group != nil {
+			b.dispatch(h.listener, h.args)
+		} else {
+			parallel = append(parallel, h)
+		}
+	}
+
+	// Each helper only marks the listeners in its own chunk
+	tooSlow := make([]bool, len(parallel))
+	var wg sync.WaitGroup
+	for start := 0; start < len(parallel); start += b.fanOut {
+		end := start + b.fanOut
+		if end > len(parallel) {
+			end = len(parallel)
+		}
+		start := start
+		chunk := parallel[start:end]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i, h := range chunk {
+				tooSlow[start+i] = !b.dispatch(h.listener, h.args)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i, h := range parallel {
+		if tooSlow[i] {
+			slow = append(slow, h.listener)
+		}
+	}
+	return
+}
@@ -0,0 +1,94 @@
+package eventually_test
+
+import (
+	events "github.com/erkkah/eventually"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestFanOut(t *testing.T) {
+	// Kept below the race detector's goroutine limit
+	const listeners = 5000
+
+	b := events.NewBus(events.WithFanOut(500))
+
+	counts := make([]int32, listeners)
+	var wg sync.WaitGroup
+	wg.Add(listeners)
+	for i := 0; i < listeners; i++ {
+		i := i
+		_, err := b.On("wide", func(n int) {
+			atomic.AddInt32(&counts[i], int32(n))
+			wg.Done()
+		})
+		if err != nil {
+			t.Fatalf("Failed to register callback: %v", err)
+		}
+	}
+
+	if err := b.Post("wide", 1); err != nil {
+		t.Fatalf("Failed to post: %v", err)
+	}
+	wg.Wait()
+
+	for i, count := range counts {
+		if count != 1 {
+			t.Fatalf("Listener %d received %d events, expected exactly one", i, count)
+		}
+	}
+}
+
+func TestFanOut_Once(t *testing.T) {
+	b := events.NewBus(events.WithFanOut(2))
+
+	var received int32
+	var wg sync.WaitGroup
+	wg.Add(5)
+	for i := 0; i < 5; i++ {
+		b.Once("wide", func() {
+			atomic.AddInt32(&received, 1)
+			wg.Done()
+		})
+	}
+
+	b.Post("wide")
+	b.Post("wide")
+	wg.Wait()
+
+	if count := b.SubscriberCount("wide"); count != 0 {
+		t.Fatalf("Expected once listeners to be removed, found %d", count)
+	}
+	if received != 5 {
+		t.Fatalf("Expected 5 deliveries, got %d", received)
+	}
+}
+
+func benchmarkFanOut(bench *testing.B, options ...events.Option) {
+	const listeners = 10000
+
+	b := events.NewBus(options...)
+	var wg sync.WaitGroup
+	for i := 0; i < listeners; i++ {
+		b.On("wide", func(i int) {
+			wg.Done()
+		})
+	}
+
+	bench.ResetTimer()
+	for i := 0; i < bench.N; i++ {
+		wg.Add(listeners)
+		b.Post("wide", i)
+		wg.Wait()
+	}
+	bench.StopTimer()
+	b.Close()
+}
+
+func BenchmarkFanOut_Serial(bench *testing.B) {
+	benchmarkFanOut(bench)
+}
+
+func BenchmarkFanOut_Parallel(bench *testing.B) {
+	benchmarkFanOut(bench, events.WithFanOut(1000))
+}
@@ -0,0 +1,60 @@
+package eventually
+
+import "fmt"
+
+// fastInvoker returns a function calling the callback without going
+// through reflection, for common callback signatures.
+// Returns nil for callbacks that need to be called using reflection.
+func fastInvoker(callback interface{}) func(args []interface{}) {
+	switch cb := callback.(type) {
+	case func():
+		return func(args []interface{}) {
+			checkArity(args, 0)
+			cb()
+		}
+	case func(string):
+		return func(args []interface{}) {
+			checkArity(args, 1)
+			cb(args[0].(string))
+		}
+	case func(int):
+		return func(args []interface{}) {
+			checkArity(args, 1)
+			cb(args[0].(int))
+		}
+	case func(interface{}):
+		return func(args []interface{}) {
+			checkArity(args, 1)
+			cb(args[0])
+		}
+	case func(string, string):
+		return func(args []interface{}) {
+			checkArity(args, 2)
+			cb(args[0].(string), args[1].(string))
+		}
+	case func(string, int):
+		return func(args []interface{}) {
+			checkArity(args, 2)
+			cb(args[0].(string), args[1].(int))
+		}
+	case func(string, interface{}):
+		return func(args []interface{}) {
+			checkArity(args, 2)
+			cb(args[0].(string), args[1])
+		}
+	case func(string, ...interface{}):
+		return func(args []interface{}) {
+			if len(args) < 1 {
+				panic("too few arguments")
+			}
+			cb(args[0].(string), args[1:]...)
+		}
+	}
+	return nil
+}
+
+func checkArity(args []interface{}, expected int) {
+	if len(args) != expected {
+		panic(fmt.Sprintf("expected %d arguments, got %d", expected, len(args)))
+	}
+}
@@ -0,0 +1,29 @@
+package eventually
+
+import "testing"
+
+// benchmarkCall measures calling a listener callback, with or without
+// the fast invoker, without the bus around it.
+func benchmarkCall(bench *testing.B, fast bool) {
+	l := buildListener("bench", func(i int) {}, false)
+	if !fast {
+		l.invoke = nil
+	}
+	args := []interface{}{42}
+
+	bench.ReportAllocs()
+	bench.ResetTimer()
+	for i := 0; i < bench.N; i++ {
+		if err := callListener(l, args); err != nil {
+			bench.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCallListener_Fast(bench *testing.B) {
+	benchmarkCall(bench, true)
+}
+
+func BenchmarkCallListener_Reflect(bench *testing.B) {
+	benchmarkCall(bench, false)
+}
@@ -0,0 +1,61 @@
+package eventually_test
+
+import (
+	events "github.com/erkkah/eventually"
+	"testing"
+)
+
+func TestFastDispatch(t *testing.T) {
+	b := events.NewBus()
+
+	received := make(chan []interface{}, 1)
+
+	b.Once("none", func() {
+		received <- nil
+	})
+	b.Once("one", func(i int) {
+		received <- []interface{}{i}
+	})
+	b.Once("two", func(s string, i int) {
+		received <- []interface{}{s, i}
+	})
+	b.Once("reflected", func(f float64, i int, s string) {
+		received <- []interface{}{f, i, s}
+	})
+
+	b.Post("none")
+	if args := <-received; len(args) != 0 {
+		t.Fatalf("Unexpected arguments: %v", args)
+	}
+
+	b.Post("one", 1)
+	if args := <-received; len(args) != 1 || args[0] != 1 {
+		t.Fatalf("Unexpected arguments: %v", args)
+	}
+
+	b.Post("two", "two", 2)
+	if args := <-received; len(args) != 2 || args[0] != "two" || args[1] != 2 {
+		t.Fatalf("Unexpected arguments: %v", args)
+	}
+
+	b.Post("reflected", 3.0, 3, "three")
+	if args := <-received; len(args) != 3 || args[0] != 3.0 || args[1] != 3 || args[2] != "three" {
+		t.Fatalf("Unexpected arguments: %v", args)
+	}
+}
+
+func TestFastDispatch_Mismatch(t *testing.T) {
+	b := events.NewBus()
+
+	crashed := make(chan error)
+	b.OnError(func(topic string, err error) {
+		crashed <- err
+	})
+
+	b.Once("one", func(i int) {
+		t.Error("Listener should not be called with mismatched arguments")
+	})
+
+	b.Post("one", "not an int")
+	<-crashed
+}
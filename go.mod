@@ -1 +1,3 @@
 module github.com/erkkah/eventually
+
+go 1.18
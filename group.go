@@ -0,0 +1,53 @@
+package eventually
+
+type groupTask struct {
+	listener Listener
+	args     []interface{}
+}
+
+// listenerGroup delivers events to a group of listeners using
+// a single goroutine.
+// Groups are owned by the dispatcher goroutine.
+type listenerGroup struct {
+	tasks    chan groupTask
+	members  int
+	key      string
+	registry map[string]*listenerGroup
+}
+
+func (b *bus) OnGrouped(topic string, group string, callback interface{}) (Listener, error) {
+	l := buildListener(topic, callback, false)
+	l.groupName = group
+	return b.addListenerRequest(l)
+}
+
+// joinGroup adds a listener to a group, creating the group if needed.
+func (b *bus) joinGroup(topic string, name string) *listenerGroup {
+	key := topic + "\x00" + name
+	group, exists := b.groups[key]
+	if !exists {
+		group = &listenerGroup{
+			tasks:    make(chan groupTask),
+			key:      key,
+			registry: b.groups,
+		}
+		b.groups[key] = group
+		b.goListener(func() {
+			for task := range group.tasks {
+				b.deliver(task.listener, task.args)
+			}
+		})
+	}
+	group.members++
+	return group
+}
+
+// leave removes a listener from the group, stopping the group
+// goroutine when the last member leaves.
+func (g *listenerGroup) leave() {
+	g.members--
+	if g.members == 0 {
+		close(g.tasks)
+		delete(g.registry, g.key)
+	}
+}
@@ -0,0 +1,44 @@
+package eventually_test
+
+import (
+	events "github.com/erkkah/eventually"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestOnGrouped(t *testing.T) {
+	b := events.NewBus()
+
+	var running int32
+	var order []int
+	var delivered sync.WaitGroup
+	delivered.Add(3)
+
+	for i := 0; i < 3; i++ {
+		i := i
+		_, err := b.OnGrouped("ping", "cheap", func() {
+			defer delivered.Done()
+			if !atomic.CompareAndSwapInt32(&running, 0, 1) {
+				t.Error("Grouped callbacks ran concurrently")
+				return
+			}
+			time.Sleep(time.Millisecond)
+			order = append(order, i)
+			atomic.StoreInt32(&running, 0)
+		})
+		if err != nil {
+			t.Fatalf("Failed to register callback: %v", err)
+		}
+	}
+
+	b.Post("ping")
+	delivered.Wait()
+
+	for i, member := range order {
+		if member != i {
+			t.Fatalf("Expected callbacks in registration order, got %v", order)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package eventually
+
+// MetricType identifies what a Metric describes.
+type MetricType int
+
+const (
+	// EventPosted is emitted when an event is accepted for delivery.
+	// The count is the number of listeners the event is handed to.
+	EventPosted MetricType = iota
+	// EventDelivered is emitted when a listener has handled an event.
+	EventDelivered
+	// ListenerPanicked is emitted when a listener fails to handle an event.
+	ListenerPanicked
+	// ListenerAdded is emitted when a listener is registered.
+	ListenerAdded
+	// ListenerRemoved is emitted when a listener is removed, by being
+	// unsubscribed or replaced, after its single event for Once, or
+	// when the bus is closed.
+	ListenerRemoved
+	// EventDropped is emitted when an event is dropped, because the bus
+	// queue was full, or for a listener that was too slow.
+	EventDropped
+)
+
+// Metric describes something that happened on a topic.
+type Metric struct {
+	Type  MetricType
+	Topic string
+	Count int
+}
+
+// WithMetrics sets a function receiving metrics from the bus.
+// The function is called from both the dispatcher and listener goroutines,
+// and must be safe for concurrent use. It should not block.
+func WithMetrics(report func(Metric)) Option {
+	return func(b *bus) {
+		b.metrics = report
+	}
+}
+
+func (b *bus) emit(metricType MetricType, topic string, count int) {
+	if b.metrics != nil {
+		b.metrics(Metric{
+			Type:  metricType,
+			Topic: topic,
+			Count: count,
+		})
+	}
+}
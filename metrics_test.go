@@ -0,0 +1,92 @@
+package eventually_test
+
+import (
+	events "github.com/erkkah/eventually"
+	"sync"
+	"testing"
+)
+
+func TestWithMetrics(t *testing.T) {
+	var lock sync.Mutex
+	counts := map[events.MetricType]int{}
+	var handled sync.WaitGroup
+	handled.Add(2)
+
+	b := events.NewBus(
+		events.WithMetrics(func(m events.Metric) {
+			lock.Lock()
+			defer lock.Unlock()
+			if m.Topic == "ping" {
+				counts[m.Type] += m.Count
+			}
+			if m.Type == events.EventDelivered || m.Type == events.ListenerPanicked {
+				handled.Done()
+			}
+		}),
+		events.WithErrorHandler(func(string, error) {}),
+	)
+
+	b.On("ping", func() {})
+	crashing, _ := b.On("ping", func() {
+		panic("Crash")
+	})
+	b.Post("ping")
+	handled.Wait()
+	b.Unsubscribe("ping", crashing)
+	// Wait for the unsubscription to be processed
+	b.Topics()
+
+	lock.Lock()
+	defer lock.Unlock()
+	expected := map[events.MetricType]int{
+		events.ListenerAdded:    2,
+		events.EventPosted:      2,
+		events.EventDelivered:   1,
+		events.ListenerPanicked: 1,
+		events.ListenerRemoved:  1,
+	}
+	for metricType, count := range expected {
+		if counts[metricType] != count {
+			t.Errorf("Expected metric %d to count %d, got %d", metricType, count, counts[metricType])
+		}
+	}
+}
+
+func TestListenerMetricsBalance(t *testing.T) {
+	var lock sync.Mutex
+	counts := map[events.MetricType]int{}
+
+	b := events.NewBus(
+		events.WithMetrics(func(m events.Metric) {
+			lock.Lock()
+			defer lock.Unlock()
+			counts[m.Type] += m.Count
+		}),
+		events.WithListenerQuarantine(),
+	)
+
+	received := make(chan bool, 1)
+	b.On("ping", func() {})
+	b.Once("ping", func() {
+		received <- true
+	})
+	b.OnTagged("ping", "tag", func() {})
+	b.OnTagged("ping", "tag", func() {})
+	b.On("count", func(int) {})
+	b.Post("ping")
+	<-received
+	if _, err := b.SetEventMap(events.EventMap{"count": {""}}); err != nil {
+		t.Fatalf("Failed to set event map: %v", err)
+	}
+	b.Close()
+
+	lock.Lock()
+	defer lock.Unlock()
+	if counts[events.ListenerAdded] != 5 {
+		t.Fatalf("Expected 5 added listeners, got %d", counts[events.ListenerAdded])
+	}
+	if counts[events.ListenerRemoved] != counts[events.ListenerAdded] {
+		t.Fatalf("Expected %d removed listeners, got %d",
+			counts[events.ListenerAdded], counts[events.ListenerRemoved])
+	}
+}
@@ -0,0 +1,66 @@
+package eventually
+
+// WithTopicConcurrency limits the number of listener callbacks for a
+// topic that can execute concurrently.
+// Deliveries exceeding the limit wait for running callbacks to finish.
+func WithTopicConcurrency(topic string, n int) Option {
+	return func(b *bus) {
+		if b.topicConcurrency == nil {
+			b.topicConcurrency = make(map[string]int)
+		}
+		b.topicConcurrency[topic] = n
+	}
+}
+
+// TopicModeInfo describes the resolved delivery semantics of a topic.
+type TopicModeInfo struct {
+	// Order is the order in which listeners handle pending events
+	Order Order
+	// Concurrency is the topic concurrency limit, or 0 if unlimited
+	Concurrency int
+	// MaxInFlight is the bus wide concurrency limit, or 0 if unlimited
+	MaxInFlight int
+	// Buffer is the number of events that can be pending for each
+	// listener, or -1 if unbounded
+	Buffer int
+	// Verified is true if the topic has a custom verifier
+	Verified bool
+	// Declared is true if the topic is declared in the event map
+	Declared bool
+	// Sync is true if listener callbacks are called on the posting
+	// goroutine, see WithSyncDispatch
+	Sync bool
+	// Executor is true if deliveries are submitted to an executor,
+	// see WithExecutor
+	Executor bool
+	// Grouped is the number of listeners delivered to by a listener
+	// group instead of the executor, see OnGrouped
+	Grouped int
+}
+
+func (b *bus) TopicMode(topic string) TopicModeInfo {
+	info := TopicModeInfo{
+		Order:       b.topicOrders[topic],
+		Concurrency: b.topicConcurrency[topic],
+		MaxInFlight: b.maxInFlight,
+		Sync:        b.syncDispatch,
+		Executor:    b.executor != nil && !b.syncDispatch,
+	}
+	if info.Order == LIFO {
+		info.Buffer = -1
+	} else {
+		info.Buffer = b.bufferSize(topic)
+	}
+	_, info.Verified = b.verifiers[topic]
+	b.runQuery(func() {
+		if b.eventMap != nil {
+			_, info.Declared = (*b.eventMap)[topic]
+		}
+		for _, l := range b.topicListeners[topic] {
+			if l.group != nil {
+				info.Grouped++
+			}
+		}
+	})
+	return info
+}
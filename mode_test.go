@@ -0,0 +1,83 @@
+package eventually_test
+
+import (
+	events "github.com/erkkah/eventually"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTopicMode(t *testing.T) {
+	b := events.NewBus(
+		events.WithTopicConcurrency("work", 3),
+		events.WithTopicOrder("stack", events.LIFO),
+		events.WithMaxInFlight(10),
+	)
+
+	mode := b.TopicMode("work")
+	if mode.Concurrency != 3 {
+		t.Fatalf("Expected concurrency limit 3, got %d", mode.Concurrency)
+	}
+	if mode.MaxInFlight != 10 {
+		t.Fatalf("Expected max in flight 10, got %d", mode.MaxInFlight)
+	}
+	if mode.Order != events.FIFO || mode.Buffer != 0 {
+		t.Fatalf("Unexpected mode for FIFO topic: %+v", mode)
+	}
+
+	mode = b.TopicMode("stack")
+	if mode.Order != events.LIFO || mode.Buffer != -1 {
+		t.Fatalf("Unexpected mode for LIFO topic: %+v", mode)
+	}
+	if mode.Sync || mode.Executor {
+		t.Fatalf("Expected asynchronous delivery on listener goroutines: %+v", mode)
+	}
+}
+
+func TestTopicMode_Delivery(t *testing.T) {
+	b := events.NewBus(events.WithExecutor(&countingExecutor{}))
+	b.On("work", func() {})
+	b.OnGrouped("work", "group", func() {})
+
+	mode := b.TopicMode("work")
+	if mode.Sync || !mode.Executor || mode.Grouped != 1 {
+		t.Fatalf("Unexpected mode for executor topic: %+v", mode)
+	}
+
+	mode = events.NewBus(events.WithSyncDispatch()).TopicMode("work")
+	if !mode.Sync || mode.Executor {
+		t.Fatalf("Unexpected mode for synchronous topic: %+v", mode)
+	}
+}
+
+func TestTopicConcurrency(t *testing.T) {
+	b := events.NewBus(events.WithTopicConcurrency("work", 1))
+
+	const listeners = 5
+	var running, maxRunning int32
+	var delivered sync.WaitGroup
+	delivered.Add(listeners * 2)
+
+	for i := 0; i < listeners; i++ {
+		b.On("work", func() {
+			defer delivered.Done()
+			if atomic.AddInt32(&running, 1) > 1 {
+				atomic.StoreInt32(&maxRunning, 2)
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt32(&running, -1)
+		})
+		b.On("other", func() {
+			delivered.Done()
+		})
+	}
+
+	b.Post("work")
+	b.Post("other")
+	delivered.Wait()
+
+	if atomic.LoadInt32(&maxRunning) > 1 {
+		t.Fatal("Expected at most one concurrent callback")
+	}
+}
@@ -0,0 +1,107 @@
+package eventually
+
+import "sync"
+
+// Order is the order in which a listener handles pending events.
+type Order int
+
+const (
+	// FIFO listeners handle the oldest pending event first.
+	FIFO Order = iota
+	// LIFO listeners handle the most recent pending event first.
+	LIFO
+)
+
+// WithTopicOrder sets the order in which listeners for a topic
+// handle pending events. Defaults to FIFO.
+//
+// LIFO listeners accept events as soon as they are posted, and keep
+// them pending until the listener callback is ready for the next one.
+// This means that the number of pending events is not bounded.
+func WithTopicOrder(topic string, order Order) Option {
+	return func(b *bus) {
+		if b.topicOrders == nil {
+			b.topicOrders = make(map[string]Order)
+		}
+		b.topicOrders[topic] = order
+	}
+}
+
+// eventStack holds pending events for a LIFO listener.
+type eventStack struct {
+	lock    sync.Mutex
+	pending *sync.Cond
+	events  [][]interface{}
+	closed  bool
+}
+
+func newEventStack() *eventStack {
+	s := &eventStack{}
+	s.pending = sync.NewCond(&s.lock)
+	return s
+}
+
+func (s *eventStack) push(evnt []interface{}) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.events = append(s.events, evnt)
+	s.pending.Signal()
+}
+
+func (s *eventStack) close() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.closed = true
+	s.pending.Signal()
+}
+
+// pop waits for the most recent pending event.
+// Returns false when the stack is closed and empty.
+func (s *eventStack) pop() ([]interface{}, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for len(s.events) == 0 && !s.closed {
+		s.pending.Wait()
+	}
+	if len(s.events) == 0 {
+		return nil, false
+	}
+	last := len(s.events) - 1
+	evnt := s.events[last]
+	s.events = s.events[:last]
+	return evnt, true
+}
+
+func (s *eventStack) size() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return len(s.events)
+}
+
+func (b *bus) startStackedListener(l Listener) Listener {
+	stack := newEventStack()
+	l.pending = stack
+
+	b.goListener(func() {
+		for {
+			evnt, alive := <-l.channel
+			if !alive {
+				break
+			}
+			stack.push(evnt)
+		}
+		stack.close()
+	})
+
+	b.goListener(func() {
+		for {
+			evnt, alive := stack.pop()
+			if !alive {
+				break
+			}
+			b.deliver(l, evnt)
+		}
+	})
+
+	return l
+}
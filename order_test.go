@@ -0,0 +1,114 @@
+package eventually_test
+
+import (
+	events "github.com/erkkah/eventually"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTopicOrder_LIFO(t *testing.T) {
+	b := events.NewBus(events.WithTopicOrder("stack", events.LIFO))
+
+	started := make(chan bool)
+	release := make(chan bool)
+	received := make(chan int, 4)
+
+	b.On("stack", func(msg int) {
+		if msg == 1 {
+			started <- true
+			<-release
+		}
+		received <- msg
+	})
+
+	b.Post("stack", 1)
+	<-started
+
+	b.Post("stack", 2)
+	b.Post("stack", 3)
+	b.Post("stack", 4)
+	// Let the last event settle on the pending stack
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+
+	for _, expected := range []int{1, 4, 3, 2} {
+		if msg := <-received; msg != expected {
+			t.Fatalf("Expected event %d, got %d", expected, msg)
+		}
+	}
+}
+
+func TestListenerBacklog(t *testing.T) {
+	b := events.NewBus(events.WithTopicOrder("stack", events.LIFO))
+
+	started := make(chan bool)
+	release := make(chan bool)
+	done := make(chan bool)
+
+	listener, _ := b.On("stack", func(msg int) {
+		if msg == 1 {
+			started <- true
+			<-release
+		}
+		if msg == 4 {
+			close(done)
+		}
+	})
+
+	b.Post("stack", 1)
+	<-started
+
+	b.Post("stack", 2)
+	b.Post("stack", 3)
+	b.Post("stack", 4)
+	// Let the last event settle on the pending stack
+	time.Sleep(10 * time.Millisecond)
+
+	if backlog := b.ListenerBacklog(listener); backlog != 3 {
+		t.Fatalf("Expected a backlog of 3 events, got %d", backlog)
+	}
+
+	close(release)
+	<-done
+}
+
+func TestListenerOrder_ConcurrentPosters(t *testing.T) {
+	b := events.NewBus(events.WithQueueLength(50))
+
+	const posters = 8
+	const posts = 200
+	const listeners = 3
+
+	var delivered sync.WaitGroup
+	delivered.Add(listeners * posters * posts)
+	received := make([][]uint64, listeners)
+	for i := 0; i < listeners; i++ {
+		i := i
+		b.OnSeq("tick", func(seq uint64, data ...interface{}) {
+			received[i] = append(received[i], seq)
+			delivered.Done()
+		})
+	}
+
+	var posting sync.WaitGroup
+	posting.Add(posters)
+	for p := 0; p < posters; p++ {
+		go func() {
+			defer posting.Done()
+			for i := 0; i < posts; i++ {
+				b.Post("tick")
+			}
+		}()
+	}
+	posting.Wait()
+	delivered.Wait()
+
+	for i, seqs := range received {
+		for j := 1; j < len(seqs); j++ {
+			if seqs[j] <= seqs[j-1] {
+				t.Fatalf("Listener %d received sequence %d after %d", i, seqs[j], seqs[j-1])
+			}
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package eventually
+
+// WithDropWhilePaused makes a paused bus drop posted events,
+// instead of keeping them for delivery when resumed.
+func WithDropWhilePaused() Option {
+	return func(b *bus) {
+		b.dropWhilePaused = true
+	}
+}
+
+func (b *bus) PauseAll() {
+	b.runQuery(func() {
+		b.paused = true
+	})
+}
+
+func (b *bus) ResumeAll() {
+	b.runQuery(func() {
+		b.paused = false
+		pending := b.pausedEvents
+		b.pausedEvents = nil
+		for _, evnt := range pending {
+			if err := b.sendEvent(evnt); err != nil && b.errorHandler != nil {
+				callErrorHandler(b.errorHandler, evnt.topic, err)
+			}
+		}
+	})
+}
+
+// sendEvent broadcasts an event, or holds it while the bus is paused.
+// Events for broken topics are rejected.
+func (b *bus) sendEvent(evnt event) (err error) {
+	if err, broken := b.brokenTopics[evnt.topic]; broken {
+		return err
+	}
+	defer b.recoverTopic(evnt.topic, &err)
+
+	if !b.paused {
+		return b.broadcast(evnt)
+	}
+	if evnt.produce != nil {
+		err = b.verifyTopic(evnt.topic)
+	} else {
+		err = b.verifyEvent(evnt)
+	}
+	if err != nil {
+		return err
+	}
+	if !b.dropWhilePaused {
+		evnt.tracker = nil
+		b.pausedEvents = append(b.pausedEvents, evnt)
+	}
+	return nil
+}
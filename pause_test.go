@@ -0,0 +1,56 @@
+package eventually_test
+
+import (
+	events "github.com/erkkah/eventually"
+	"testing"
+	"time"
+)
+
+func TestPauseAll(t *testing.T) {
+	b := events.NewBus()
+
+	received := make(chan string, 10)
+	b.On("a", func(i int) {
+		received <- "a"
+	})
+	b.On("b", func(i int) {
+		received <- "b"
+	})
+
+	b.PauseAll()
+	b.Post("a", 1)
+	b.Post("b", 2)
+
+	select {
+	case topic := <-received:
+		t.Fatalf("Unexpected %q event while paused", topic)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	b.ResumeAll()
+
+	topics := map[string]bool{}
+	topics[<-received] = true
+	topics[<-received] = true
+	if !topics["a"] || !topics["b"] {
+		t.Fatalf("Expected events on both topics, got %v", topics)
+	}
+}
+
+func TestPauseAll_Drop(t *testing.T) {
+	b := events.NewBus(events.WithDropWhilePaused())
+
+	received := make(chan int, 10)
+	b.On("a", func(i int) {
+		received <- i
+	})
+
+	b.PauseAll()
+	b.Post("a", 1)
+	b.ResumeAll()
+	b.Post("a", 2)
+
+	if i := <-received; i != 2 {
+		t.Fatalf("Expected event posted after resume, got %d", i)
+	}
+}
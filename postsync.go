@@ -0,0 +1,64 @@
+package eventually
+
+import "sync"
+
+// deliveryTracker tracks the deliveries of an event posted using PostSync.
+type deliveryTracker struct {
+	pending sync.WaitGroup
+	lock    sync.Mutex
+	errs    errorList
+}
+
+// trackedArgs wraps the arguments of a tracked event, on their way
+// to the listener goroutine.
+type trackedArgs struct {
+	args    []interface{}
+	tracker *deliveryTracker
+}
+
+// track wraps event arguments for a tracked delivery.
+// Called on the dispatcher goroutine.
+func (t *deliveryTracker) track(args []interface{}) []interface{} {
+	t.pending.Add(1)
+	return []interface{}{trackedArgs{args, t}}
+}
+
+// done is called on the listener goroutine after a tracked delivery.
+func (t *deliveryTracker) done(err error) {
+	if err != nil {
+		t.lock.Lock()
+		t.errs = append(t.errs, err)
+		t.lock.Unlock()
+	}
+	t.pending.Done()
+}
+
+// untrack unwraps the arguments of a tracked delivery.
+func untrack(args []interface{}) ([]interface{}, *deliveryTracker) {
+	if len(args) == 1 {
+		if tracked, isTracked := args[0].(trackedArgs); isTracked {
+			return tracked.args, tracked.tracker
+		}
+	}
+	return args, nil
+}
+
+func (b *bus) PostSync(topic string, data ...interface{}) error {
+	if b.syncDispatch {
+		return b.Post(topic, data...)
+	}
+	tracker := &deliveryTracker{}
+	err := b.postEvent(event{
+		topic:   topic,
+		data:    data,
+		tracker: tracker,
+	})
+	if err != nil {
+		return err
+	}
+	tracker.pending.Wait()
+	if len(tracker.errs) > 0 {
+		return tracker.errs
+	}
+	return nil
+}
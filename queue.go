@@ -0,0 +1,55 @@
+package eventually
+
+import "errors"
+
+// FullQueuePolicy decides what happens to events posted while the
+// bus queue is full.
+type FullQueuePolicy int
+
+const (
+	// Block makes posting wait for room in the queue.
+	Block FullQueuePolicy = iota
+	// DropNewest discards the posted event.
+	DropNewest
+	// ReturnError makes posting fail with ErrQueueFull.
+	ReturnError
+)
+
+// ErrQueueFull is returned when posting to a full queue using
+// the ReturnError policy.
+var ErrQueueFull = errors.New("Queue full")
+
+// WithFullQueuePolicy sets what happens to events posted while the bus
+// queue is full. Defaults to Block.
+// Dropped events are counted in the topic Stats.
+func WithFullQueuePolicy(policy FullQueuePolicy) Option {
+	return func(b *bus) {
+		b.fullQueuePolicy = policy
+	}
+}
+
+// enqueueEvent hands an event request to the dispatcher goroutine,
+// applying the full queue policy.
+// Returns false if the event was not queued, with an error unless
+// it was dropped.
+func (b *bus) enqueueEvent(request busRequest) (bool, error) {
+	if b.fullQueuePolicy == Block {
+		err := b.enqueue(b.requests, request)
+		return err == nil, err
+	}
+	select {
+	case <-b.closed:
+		return false, ErrBusClosed
+	default:
+	}
+	select {
+	case b.requests <- request:
+		return true, nil
+	default:
+	}
+	if b.fullQueuePolicy == DropNewest {
+		b.countDrop(request.event.topic)
+		return false, nil
+	}
+	return false, ErrQueueFull
+}
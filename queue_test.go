@@ -0,0 +1,53 @@
+package eventually_test
+
+import (
+	events "github.com/erkkah/eventually"
+	"testing"
+	"time"
+)
+
+// stallBus blocks the bus dispatcher and fills its queue,
+// returning a function that releases it.
+func stallBus(b events.Bus) func() {
+	release := make(chan bool)
+	b.On("slow", func() {
+		<-release
+	})
+	b.Post("slow")
+	go b.Post("slow")
+	time.Sleep(5 * time.Millisecond)
+	b.PostNoWait("fill")
+	return func() {
+		close(release)
+	}
+}
+
+func TestFullQueuePolicy_ReturnError(t *testing.T) {
+	b := events.NewBus(
+		events.WithQueueLength(1),
+		events.WithFullQueuePolicy(events.ReturnError),
+	)
+	release := stallBus(b)
+	defer release()
+
+	if err := b.Post("event"); err != events.ErrQueueFull {
+		t.Fatalf("Expected ErrQueueFull, got %v", err)
+	}
+}
+
+func TestFullQueuePolicy_DropNewest(t *testing.T) {
+	b := events.NewBus(
+		events.WithQueueLength(1),
+		events.WithFullQueuePolicy(events.DropNewest),
+	)
+	release := stallBus(b)
+
+	if err := b.Post("event"); err != nil {
+		t.Fatalf("Expected dropped event to be accepted, got %v", err)
+	}
+	release()
+
+	if dropped := b.Stats("event").Dropped; dropped != 1 {
+		t.Fatalf("Expected 1 dropped event, got %d", dropped)
+	}
+}
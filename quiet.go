@@ -0,0 +1,80 @@
+package eventually
+
+import "time"
+
+// quietGate holds events for a listener until a topic has been quiet
+// for a while.
+// Gates are owned by the dispatcher goroutine.
+type quietGate struct {
+	topic   string
+	quiet   time.Duration
+	last    time.Time
+	held    []event
+	waiting bool
+}
+
+// touchQuietGates records activity on a topic for the gates watching it.
+func (b *bus) touchQuietGates(topic string) {
+	for _, gate := range b.quietGates {
+		if gate.topic == topic || (isPattern(gate.topic) && matchesPattern(gate.topic, topic)) {
+			gate.last = time.Now()
+		}
+	}
+}
+
+// removeQuietGate stops watching the quiet topic of a removed listener.
+func (b *bus) removeQuietGate(removed *quietGate) {
+	keepList := []*quietGate{}
+	for _, gate := range b.quietGates {
+		if gate != removed {
+			keepList = append(keepList, gate)
+		}
+	}
+	b.quietGates = keepList
+}
+
+func (b *bus) OnAfterQuiet(deliverTopic string, quietTopic string, quiet time.Duration, callback interface{}) (Listener, error) {
+	l := b.newListener(deliverTopic, callback, false)
+	id := l.id
+	gate := &quietGate{topic: quietTopic, quiet: quiet}
+
+	// Events are held on the dispatcher goroutine, and released by
+	// a timer, so that waiting for quiet does not hold up the bus
+	var release func()
+	schedule := func(after time.Duration) {
+		gate.waiting = true
+		time.AfterFunc(after, func() {
+			b.runQuery(release)
+		})
+	}
+	release = func() {
+		gate.waiting = false
+		if remaining := gate.quiet - time.Since(gate.last); remaining > 0 {
+			schedule(remaining)
+			return
+		}
+		held := gate.held
+		gate.held = nil
+		for _, listener := range b.topicListeners[deliverTopic] {
+			if listener.id == id {
+				for i := range held {
+					b.dispatch(listener, listenerArguments(listener, &held[i]))
+				}
+				return
+			}
+		}
+	}
+	l.hold = func(evnt *event) bool {
+		remaining := gate.quiet - time.Since(gate.last)
+		if remaining <= 0 && len(gate.held) == 0 {
+			return false
+		}
+		gate.held = append(gate.held, *evnt)
+		if !gate.waiting {
+			schedule(remaining)
+		}
+		return true
+	}
+	l.gate = gate
+	return b.addListenerRequest(l)
+}
@@ -0,0 +1,65 @@
+package eventually_test
+
+import (
+	events "github.com/erkkah/eventually"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestOnAfterQuiet(t *testing.T) {
+	b := events.NewBus()
+
+	const quiet = 200 * time.Millisecond
+	rendered := make(chan time.Time, 1)
+	_, err := b.OnAfterQuiet("render", "input", quiet, func() {
+		rendered <- time.Now()
+	})
+	if err != nil {
+		t.Fatalf("Failed to register callback: %v", err)
+	}
+	other := make(chan bool, 1)
+	b.On("other", func() {
+		other <- true
+	})
+
+	lastInput := time.Now()
+	b.Post("input")
+	b.Post("render")
+
+	// Other topics keep flowing while the render event is held
+	b.Post("other")
+	select {
+	case <-other:
+	case <-time.After(time.Second):
+		t.Fatal("Other event was held up")
+	}
+	if len(rendered) != 0 {
+		t.Fatal("Event delivered before the quiet period")
+	}
+
+	select {
+	case at := <-rendered:
+		if silence := at.Sub(lastInput); silence < quiet {
+			t.Fatalf("Delivered after %v of silence, expected at least %v", silence, quiet)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Event was never delivered")
+	}
+}
+
+func TestOnAfterQuiet_Hidden(t *testing.T) {
+	b := events.NewBus()
+
+	b.OnAfterQuiet("render", "input", time.Millisecond, func() {})
+
+	if b.HasListeners("input") {
+		t.Fatal("Quiet topic should have no listeners")
+	}
+	if count := b.SubscriberCount("input"); count != 0 {
+		t.Fatalf("Expected no subscribers to the quiet topic, got %d", count)
+	}
+	if topics := b.Topics(); !reflect.DeepEqual(topics, []string{"render"}) {
+		t.Fatalf("Unexpected topics %v", topics)
+	}
+}
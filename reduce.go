@@ -0,0 +1,36 @@
+package eventually
+
+import "time"
+
+func (b *bus) OnReduced(topic string, window time.Duration, reduce func(acc, next []interface{}) []interface{}, callback interface{}) (Listener, error) {
+	l := b.newListener(topic, callback, false)
+	id := l.id
+
+	// Only accessed on the dispatcher goroutine
+	var acc []interface{}
+	pending := false
+
+	flush := func() {
+		args := acc
+		acc, pending = nil, false
+		for _, listener := range b.topicListeners[topic] {
+			if listener.id == id {
+				b.dispatch(listener, args)
+				return
+			}
+		}
+	}
+	l.hold = func(evnt *event) bool {
+		if pending {
+			acc = reduce(acc, evnt.data)
+			return true
+		}
+		acc = evnt.data
+		pending = true
+		time.AfterFunc(window, func() {
+			b.runQuery(flush)
+		})
+		return true
+	}
+	return b.addListenerRequest(l)
+}
@@ -0,0 +1,40 @@
+package eventually_test
+
+import (
+	events "github.com/erkkah/eventually"
+	"testing"
+	"time"
+)
+
+func TestOnReduced(t *testing.T) {
+	b := events.NewBus()
+
+	sum := func(acc, next []interface{}) []interface{} {
+		return []interface{}{acc[0].(int) + next[0].(int)}
+	}
+	totals := make(chan int, 3)
+	_, err := b.OnReduced("delta", 20*time.Millisecond, sum, func(total int) {
+		totals <- total
+	})
+	if err != nil {
+		t.Fatalf("Failed to register callback: %v", err)
+	}
+
+	b.Post("delta", 1)
+	b.Post("delta", 2)
+	b.Post("delta", 3)
+
+	if total := <-totals; total != 6 {
+		t.Fatalf("Expected total 6, got %d", total)
+	}
+	select {
+	case total := <-totals:
+		t.Fatalf("Unexpected extra total %d", total)
+	case <-time.After(40 * time.Millisecond):
+	}
+
+	b.Post("delta", 4)
+	if total := <-totals; total != 4 {
+		t.Fatalf("Expected new window total 4, got %d", total)
+	}
+}
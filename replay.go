@@ -0,0 +1,72 @@
+package eventually
+
+// WithReplay makes the bus keep the last n events posted to a topic, and
+// replay them to new listeners of the topic, before any later events.
+// Once listeners only receive the oldest kept event.
+// Wildcard listeners do not receive replayed events.
+func WithReplay(topic string, n int) Option {
+	return func(b *bus) {
+		if b.replays == nil {
+			b.replays = make(map[string]*replayBuffer)
+		}
+		b.replays[topic] = &replayBuffer{size: n}
+	}
+}
+
+// replayBuffer keeps the most recent events of a topic.
+// Replay buffers are owned by the dispatcher goroutine.
+type replayBuffer struct {
+	events []event
+	size   int
+}
+
+func (r *replayBuffer) add(evnt event) {
+	if r.size <= 0 {
+		return
+	}
+	if len(r.events) == r.size {
+		copy(r.events, r.events[1:])
+		r.events = r.events[:len(r.events)-1]
+	}
+	r.events = append(r.events, evnt)
+}
+
+// recordReplay keeps a delivered event, if its topic is replayed.
+func (b *bus) recordReplay(evnt event) {
+	if buffer, replayed := b.replays[evnt.topic]; replayed {
+		evnt.tracker = nil
+		buffer.add(evnt)
+	}
+}
+
+// replay hands the kept events of the listener topic to a new listener.
+// Returns false if a once listener was consumed by a replayed event.
+func (b *bus) replay(l Listener) bool {
+	buffer, replayed := b.replays[l.topic]
+	if !replayed {
+		return true
+	}
+	for i := range buffer.events {
+		evnt := &buffer.events[i]
+		if l.match != nil && !l.match(evnt) {
+			continue
+		}
+		if l.hold != nil && l.hold(evnt) {
+			continue
+		}
+		b.dispatch(l, listenerArguments(l, evnt))
+		if l.once {
+			l.close()
+			return false
+		}
+	}
+	return true
+}
+
+func (b *bus) OnWithReplaySince(topic string, sinceSeq uint64, callback interface{}) (Listener, error) {
+	l := b.newListener(topic, callback, false)
+	l.match = func(evnt *event) bool {
+		return evnt.seq > sinceSeq
+	}
+	return b.addListenerRequest(l)
+}
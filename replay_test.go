@@ -0,0 +1,81 @@
+package eventually_test
+
+import (
+	events "github.com/erkkah/eventually"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWithReplay(t *testing.T) {
+	b := events.NewBus(events.WithReplay("status", 2))
+
+	for i := 1; i <= 3; i++ {
+		b.Post("status", i)
+	}
+
+	received := make(chan int, 4)
+	b.On("status", func(n int) {
+		received <- n
+	})
+	once := make(chan int, 4)
+	b.Once("status", func(n int) {
+		once <- n
+	})
+	b.Post("status", 4)
+
+	var replayed []int
+	for i := 0; i < 3; i++ {
+		replayed = append(replayed, <-received)
+	}
+	if expected := []int{2, 3, 4}; !reflect.DeepEqual(replayed, expected) {
+		t.Fatalf("Expected %v, got %v", expected, replayed)
+	}
+
+	if n := <-once; n != 2 {
+		t.Fatalf("Expected once listener to get oldest kept event, got %d", n)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if len(once) != 0 {
+		t.Fatalf("Once listener called %d extra times", len(once))
+	}
+}
+
+func TestOnWithReplaySince(t *testing.T) {
+	b := events.NewBus(events.WithReplay("status", 10))
+
+	for i := 1; i <= 5; i++ {
+		b.Post("status", i)
+	}
+
+	type received struct {
+		seq uint64
+		n   int
+	}
+	seqs := make(chan received, 5)
+	b.OnSeq("status", func(seq uint64, data ...interface{}) {
+		seqs <- received{seq, data[0].(int)}
+	})
+	for i := 1; i <= 5; i++ {
+		if r := <-seqs; r.seq != uint64(r.n) {
+			t.Fatalf("Expected sequence %d for event %d, got %d", r.n, r.n, r.seq)
+		}
+	}
+
+	replayed := make(chan int, 5)
+	_, err := b.OnWithReplaySince("status", 3, func(n int) {
+		replayed <- n
+	})
+	if err != nil {
+		t.Fatalf("Failed to register callback: %v", err)
+	}
+	b.Post("status", 6)
+
+	var got []int
+	for i := 0; i < 3; i++ {
+		got = append(got, <-replayed)
+	}
+	if expected := []int{4, 5, 6}; !reflect.DeepEqual(got, expected) {
+		t.Fatalf("Expected %v, got %v", expected, got)
+	}
+}
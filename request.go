@@ -0,0 +1,62 @@
+package eventually
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ReplyToHeader is the event header holding the reply topic of requests
+// posted using Request.
+const ReplyToHeader = "reply-to"
+
+// replyTopicPrefix starts all reply topics. Reply topics are not
+// checked against the event map.
+const replyTopicPrefix = "eventually.reply."
+
+func isReplyTopic(topic string) bool {
+	return strings.HasPrefix(topic, replyTopicPrefix)
+}
+
+// Request posts an event to a topic, and waits for a single reply.
+//
+// Each request gets a unique reply topic, passed to responders in the
+// ReplyToHeader event header. Responders register using OnWithHeaders,
+// and reply using Respond. Only the first reply is returned, and the reply
+// listener is removed when the reply arrives or the request times out.
+func Request(b Bus, topic string, timeout time.Duration, data ...interface{}) ([]interface{}, error) {
+	replyTopic := replyTopicPrefix + newEventID()
+	replies := make(chan []interface{}, 1)
+	listener, err := b.Once(replyTopic, handlerFunc(func(_ string, reply ...interface{}) {
+		replies <- reply
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	err = b.PostWithHeaders(topic, map[string]string{ReplyToHeader: replyTopic}, data...)
+	if err != nil {
+		b.Unsubscribe(replyTopic, listener)
+		return nil, err
+	}
+
+	select {
+	case reply := <-replies:
+		return reply, nil
+	case <-timer.C:
+		b.Unsubscribe(replyTopic, listener)
+		return nil, fmt.Errorf("Timed out waiting for reply to %q", topic)
+	}
+}
+
+// Respond posts a reply to a request, using the headers of the request.
+func Respond(b Bus, headers map[string]string, data ...interface{}) error {
+	replyTopic, found := headers[ReplyToHeader]
+	if !found || !isReplyTopic(replyTopic) {
+		return fmt.Errorf("Not a request, no reply topic")
+	}
+	return b.Post(replyTopic, data...)
+}
@@ -0,0 +1,42 @@
+package eventually
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRequestForgetsReplyTopics(t *testing.T) {
+	b := NewBus().(*bus)
+
+	b.OnWithHeaders("double", func(headers map[string]string, data ...interface{}) {
+		Respond(b, headers, data[0].(int)*2)
+	})
+
+	for i := 0; i < 100; i++ {
+		if _, err := Request(b, "double", time.Second, i); err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+	}
+	if _, err := Request(b, "ignored", 10*time.Millisecond); err == nil {
+		t.Fatal("Expected request without responder to time out")
+	}
+
+	var listeners, rates int
+	b.runQuery(func() {
+		listeners = len(b.topicListeners)
+		rates = len(b.rates)
+	})
+	b.countersLock.Lock()
+	counters := len(b.counters)
+	b.countersLock.Unlock()
+
+	if listeners != 1 {
+		t.Fatalf("Expected listeners for 1 topic, got %d", listeners)
+	}
+	if rates != 2 {
+		t.Fatalf("Expected rates for 2 topics, got %d", rates)
+	}
+	if counters != 2 {
+		t.Fatalf("Expected counters for 2 topics, got %d", counters)
+	}
+}
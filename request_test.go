@@ -0,0 +1,33 @@
+package eventually_test
+
+import (
+	events "github.com/erkkah/eventually"
+	"testing"
+	"time"
+)
+
+func TestRequest(t *testing.T) {
+	b := events.NewBus(events.WithEventMap(events.EventMap{
+		"double":  {0},
+		"ignored": {},
+	}))
+
+	b.OnWithHeaders("double", func(headers map[string]string, data ...interface{}) {
+		events.Respond(b, headers, data[0].(int)*2)
+	})
+
+	reply, err := events.Request(b, "double", time.Second, 21)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	if len(reply) != 1 || reply[0] != 42 {
+		t.Fatalf("Unexpected reply: %v", reply)
+	}
+
+	if _, err := events.Request(b, "ignored", 10*time.Millisecond); err == nil {
+		t.Fatal("Expected request without responder to time out")
+	}
+	if topics := b.Topics(); len(topics) != 1 || topics[0] != "double" {
+		t.Fatalf("Expected reply listeners to be removed, got topics %v", topics)
+	}
+}
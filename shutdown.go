@@ -0,0 +1,70 @@
+package eventually
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+func (b *bus) Shutdown(ctx context.Context) error {
+	atomic.StoreInt32(&b.draining, 1)
+	b.closeOnce.Do(func() {
+		close(b.closed)
+	})
+
+	select {
+	case <-b.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	// Deliveries handed to an executor or to synchronous posters
+	// are not run by listener goroutines, and are counted separately
+	ticker := time.NewTicker(time.Millisecond)
+	defer ticker.Stop()
+	for atomic.LoadInt32(&b.goroutines) > 0 || atomic.LoadInt32(&b.outstanding) > 0 {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// drainRequests handles the requests queued before the bus was shut down.
+// Called on the dispatcher goroutine.
+func (b *bus) drainRequests() {
+	for {
+		select {
+		case request := <-b.control:
+			b.handleRequest(request)
+		case request := <-b.requests:
+			b.handleRequest(request)
+		default:
+			return
+		}
+	}
+}
+
+// WithCloseNotification makes the bus post an event without arguments to
+// the topic when it is closed, after any events drained by Shutdown, so
+// that listeners can clean up. The event is handed to listeners before
+// they are stopped, and is not checked against the event map.
+func WithCloseNotification(topic string) Option {
+	return func(b *bus) {
+		b.closeTopic = topic
+	}
+}
+
+// notifyClose posts the close notification, if there is one.
+// Called on the dispatcher goroutine.
+func (b *bus) notifyClose() {
+	if b.closeTopic == "" {
+		return
+	}
+	b.publishRecovered(event{topic: b.closeTopic})
+	// There is no poster to deliver synchronous events on, deliver
+	// them after any backlogged events
+	b.flushSyncDeliveries()
+}
@@ -0,0 +1,114 @@
+package eventually_test
+
+import (
+	"context"
+	events "github.com/erkkah/eventually"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestShutdown(t *testing.T) {
+	b := events.NewBus(events.WithQueueLength(100))
+
+	var received int32
+	b.On("work", func(i int) {
+		time.Sleep(time.Millisecond)
+		atomic.AddInt32(&received, 1)
+	})
+
+	for i := 0; i < 20; i++ {
+		b.PostNoWait("work", i)
+	}
+
+	if err := b.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Failed to shut down: %v", err)
+	}
+	if count := atomic.LoadInt32(&received); count != 20 {
+		t.Fatalf("Expected all 20 events to be delivered, got %d", count)
+	}
+
+	if err := b.Post("work", 21); err != events.ErrBusClosed {
+		t.Fatalf("Expected ErrBusClosed after shutdown, got %v", err)
+	}
+	if _, err := b.On("work", func(i int) {}); err != events.ErrBusClosed {
+		t.Fatalf("Expected ErrBusClosed after shutdown, got %v", err)
+	}
+}
+
+func TestShutdown_Timeout(t *testing.T) {
+	b := events.NewBus()
+
+	release := make(chan bool)
+	b.On("stuck", func() {
+		<-release
+	})
+	b.Post("stuck")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := b.Shutdown(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("Expected deadline exceeded, got %v", err)
+	}
+	if err := b.Post("stuck"); err != events.ErrBusClosed {
+		t.Fatalf("Expected ErrBusClosed after shutdown, got %v", err)
+	}
+
+	close(release)
+	if err := b.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Failed to complete shutdown: %v", err)
+	}
+}
+
+func TestShutdown_Executor(t *testing.T) {
+	b := events.NewBus(events.WithExecutor(&countingExecutor{}))
+
+	started := make(chan bool, 3)
+	release := make(chan bool)
+	var received int32
+	b.On("work", func(i int) {
+		started <- true
+		<-release
+		atomic.AddInt32(&received, 1)
+	})
+	for i := 0; i < 3; i++ {
+		b.Post("work", i)
+		<-started
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := b.Shutdown(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("Expected shutdown to wait for executor tasks, got %v", err)
+	}
+
+	close(release)
+	if err := b.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Failed to complete shutdown: %v", err)
+	}
+	if count := atomic.LoadInt32(&received); count != 3 {
+		t.Fatalf("Expected all 3 events to be delivered, got %d", count)
+	}
+}
+
+func TestWithCloseNotification(t *testing.T) {
+	for _, sync := range []bool{false, true} {
+		options := []events.Option{events.WithCloseNotification("bus.closed")}
+		if sync {
+			options = append(options, events.WithSyncDispatch())
+		}
+		b := events.NewBus(options...)
+
+		notified := make(chan bool, 1)
+		b.On("bus.closed", func() {
+			notified <- true
+		})
+
+		b.Close()
+		select {
+		case <-notified:
+		case <-time.After(time.Second):
+			t.Fatalf("Expected close notification, sync: %v", sync)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package eventually
+
+import (
+	"errors"
+	"time"
+)
+
+// SlowListenerPolicy decides what happens when a listener does not take
+// an event in time.
+type SlowListenerPolicy int
+
+const (
+	// WaitForListener makes delivery wait until the listener takes the event.
+	WaitForListener SlowListenerPolicy = iota
+	// DropForListener discards the event for the slow listener.
+	DropForListener
+	// UnsubscribeListener discards the event, and unsubscribes the
+	// slow listener.
+	UnsubscribeListener
+)
+
+// ErrSlowListener is passed to the error handler when an event is not
+// delivered to a listener, because the listener was too slow.
+var ErrSlowListener = errors.New("Listener too slow")
+
+// WithSlowListenerPolicy sets what happens when a listener does not take
+// an event within the deadline, because it is still busy with earlier
+// events. Defaults to WaitForListener, letting a single slow listener
+// hold up the whole bus.
+// Events not delivered to slow listeners are counted as dropped, and
+// reported to the error handler, if there is one, as ErrSlowListener.
+// Grouped listeners are always waited for.
+func WithSlowListenerPolicy(policy SlowListenerPolicy, deadline time.Duration) Option {
+	return func(b *bus) {
+		b.slowListenerPolicy = policy
+		b.slowListenerDeadline = deadline
+	}
+}
+
+// send hands an event to a listener, applying the slow listener policy.
+// Returns false if the listener was too slow.
+func (b *bus) send(l Listener, args []interface{}) bool {
+	if b.slowListenerPolicy == WaitForListener || l.group != nil {
+		l.send(args)
+		return true
+	}
+	timer := time.NewTimer(b.slowListenerDeadline)
+	defer timer.Stop()
+	select {
+	case l.channel <- args:
+		return true
+	case <-timer.C:
+	}
+	b.handled(l)
+	b.countDrop(l.topic)
+	if _, tracker := untrack(args); tracker != nil {
+		tracker.done(ErrSlowListener)
+	}
+	if b.errorHandler != nil {
+		callErrorHandler(b.errorHandler, l.topic, ErrSlowListener)
+	}
+	return false
+}
+
+// removeSlowListeners unsubscribes listeners that were too slow to
+// take an event, if the policy says so.
+// Called on the dispatcher goroutine.
+func (b *bus) removeSlowListeners(slow []Listener) {
+	if b.slowListenerPolicy != UnsubscribeListener {
+		return
+	}
+	for _, l := range slow {
+		b.removeListener(l, false)
+	}
+}
@@ -0,0 +1,91 @@
+package eventually_test
+
+import (
+	events "github.com/erkkah/eventually"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSlowListenerPolicy_Drop(t *testing.T) {
+	var dropped int32
+	b := events.NewBus(
+		events.WithSlowListenerPolicy(events.DropForListener, 5*time.Millisecond),
+		events.WithMetrics(func(m events.Metric) {
+			if m.Type == events.EventDropped {
+				atomic.AddInt32(&dropped, int32(m.Count))
+			}
+		}),
+	)
+
+	errors := make(chan error, 3)
+	b.OnError(func(topic string, err error) {
+		errors <- err
+	})
+
+	release := make(chan bool)
+	b.On("work", func(i int) {
+		<-release
+	})
+	served := make(chan int, 3)
+	b.On("work", func(i int) {
+		served <- i
+	})
+
+	for i := 0; i < 3; i++ {
+		if err := b.Post("work", i); err != nil {
+			t.Fatalf("Failed to post: %v", err)
+		}
+	}
+	for i := 0; i < 3; i++ {
+		if n := <-served; n != i {
+			t.Fatalf("Expected event %d, got %d", i, n)
+		}
+	}
+	// The slow listener took the first event, and missed the rest
+	for i := 0; i < 2; i++ {
+		if err := <-errors; err != events.ErrSlowListener {
+			t.Fatalf("Expected ErrSlowListener, got %v", err)
+		}
+	}
+	close(release)
+
+	if n := atomic.LoadInt32(&dropped); n != 2 {
+		t.Fatalf("Expected 2 dropped event metrics, got %d", n)
+	}
+	stats := b.Stats("work")
+	if stats.Dropped != 2 {
+		t.Fatalf("Expected 2 dropped events in stats, got %d", stats.Dropped)
+	}
+	if rate := b.SuccessRate("work"); rate >= 1 {
+		t.Fatalf("Expected drops to lower the success rate, got %v", rate)
+	}
+
+	if count := b.SubscriberCount("work"); count != 2 {
+		t.Fatalf("Expected slow listener to stay subscribed, found %d listeners", count)
+	}
+}
+
+func TestSlowListenerPolicy_Unsubscribe(t *testing.T) {
+	b := events.NewBus(events.WithSlowListenerPolicy(events.UnsubscribeListener, 5*time.Millisecond))
+	b.OnError(func(topic string, err error) {})
+
+	release := make(chan bool)
+	defer close(release)
+	b.On("work", func(i int) {
+		<-release
+	})
+	served := make(chan int, 3)
+	b.On("work", func(i int) {
+		served <- i
+	})
+
+	for i := 0; i < 3; i++ {
+		b.Post("work", i)
+		<-served
+	}
+
+	if count := b.SubscriberCount("work"); count != 1 {
+		t.Fatalf("Expected slow listener to be unsubscribed, found %d listeners", count)
+	}
+}
@@ -0,0 +1,91 @@
+package eventually
+
+import (
+	"bytes"
+	"net/http"
+)
+
+type topicEvent struct {
+	topic string
+	data  []interface{}
+}
+
+// SSEHandler returns an http.Handler that streams events posted to the
+// given topics to HTTP clients as server-sent events.
+//
+// Each request subscribes to the topics for as long as the request lasts.
+// Events are sent with the topic as event type, and the codec encoded
+// event as data. Events that cannot be encoded are skipped.
+// Events are dropped for clients that do not keep up, so that slow or
+// disconnected clients never hold up the bus.
+func SSEHandler(b Bus, topics []string, codec Codec) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+			return
+		}
+
+		ctx := r.Context()
+		events := make(chan topicEvent, sseBuffer)
+
+		for _, topic := range topics {
+			ch, listener, err := b.Subscribe(topic, sseBuffer)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			defer b.Unsubscribe(topic, listener)
+
+			// Keeps draining the subscription until it is closed after
+			// unsubscribing, dropping events the client cannot take
+			go func(topic string) {
+				for data := range ch {
+					select {
+					case events <- topicEvent{topic, data}:
+					default:
+					}
+				}
+			}(topic)
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		for {
+			select {
+			case evnt := <-events:
+				frame, err := codec.Encode(evnt.topic, evnt.data)
+				if err != nil {
+					continue
+				}
+				if _, err := w.Write(sseFrame(evnt.topic, frame)); err != nil {
+					return
+				}
+				flusher.Flush()
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+}
+
+// sseBuffer is the number of events buffered for each client.
+const sseBuffer = 16
+
+func sseFrame(topic string, frame []byte) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("event: ")
+	buf.WriteString(topic)
+	buf.WriteString("\n")
+	for _, line := range bytes.Split(frame, []byte("\n")) {
+		buf.WriteString("data: ")
+		buf.Write(line)
+		buf.WriteString("\n")
+	}
+	buf.WriteString("\n")
+	return buf.Bytes()
+}
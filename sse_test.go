@@ -0,0 +1,111 @@
+package eventually_test
+
+import (
+	"bufio"
+	"context"
+	events "github.com/erkkah/eventually"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSSEHandler(t *testing.T) {
+	b := events.NewBus()
+
+	server := httptest.NewServer(events.SSEHandler(b, []string{"hello"}, events.JSONCodec{}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	request, _ := http.NewRequest("GET", server.URL, nil)
+	response, err := http.DefaultClient.Do(request.WithContext(ctx))
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer response.Body.Close()
+
+	if contentType := response.Header.Get("Content-Type"); contentType != "text/event-stream" {
+		t.Fatalf("Unexpected content type %q", contentType)
+	}
+
+	if err := b.WaitForListeners("hello", 1, time.Second); err != nil {
+		t.Fatalf("Handler did not subscribe: %v", err)
+	}
+	b.Post("hello", "world", 42)
+
+	reader := bufio.NewReader(response.Body)
+	expected := []string{
+		"event: hello",
+		`data: {"topic":"hello","data":["world",42]}`,
+	}
+	for _, line := range expected {
+		received, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Failed to read event stream: %v", err)
+		}
+		if strings.TrimSpace(received) != line {
+			t.Fatalf("Expected %q, got %q", line, received)
+		}
+	}
+}
+
+// stuckWriter is a streaming response writer that blocks writes
+// until released.
+type stuckWriter struct {
+	header  http.Header
+	release chan bool
+}
+
+func (w *stuckWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *stuckWriter) WriteHeader(int) {}
+
+func (w *stuckWriter) Flush() {}
+
+func (w *stuckWriter) Write(data []byte) (int, error) {
+	<-w.release
+	return len(data), nil
+}
+
+func TestSSEHandler_StuckClient(t *testing.T) {
+	b := events.NewBus()
+	handler := events.SSEHandler(b, []string{"hello"}, events.JSONCodec{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	request, _ := http.NewRequest("GET", "/", nil)
+	writer := &stuckWriter{header: http.Header{}, release: make(chan bool)}
+	served := make(chan bool)
+	go func() {
+		handler.ServeHTTP(writer, request.WithContext(ctx))
+		close(served)
+	}()
+
+	if err := b.WaitForListeners("hello", 1, time.Second); err != nil {
+		t.Fatalf("Handler did not subscribe: %v", err)
+	}
+
+	posted := make(chan bool)
+	go func() {
+		for i := 0; i < 100; i++ {
+			b.Post("hello", i)
+		}
+		close(posted)
+	}()
+	select {
+	case <-posted:
+	case <-time.After(time.Second):
+		t.Fatal("Stuck client held up the bus")
+	}
+
+	cancel()
+	close(writer.release)
+	<-served
+	if count := b.SubscriberCount("hello"); count != 0 {
+		t.Fatalf("Expected handler to unsubscribe, found %d listeners", count)
+	}
+}
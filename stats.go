@@ -0,0 +1,207 @@
+package eventually
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+const (
+	rateResolution = 10 * time.Millisecond
+	rateBuckets    = 512
+
+	// MaxRateWindow is the longest time window supported by RateStats.
+	MaxRateWindow = rateResolution * (rateBuckets - 1)
+)
+
+type rateBucket struct {
+	slot  int64
+	count int
+}
+
+// rateCounter keeps a ring of event counts per time slot.
+type rateCounter struct {
+	buckets [rateBuckets]rateBucket
+}
+
+func rateSlot(t time.Time) int64 {
+	return t.UnixNano() / int64(rateResolution)
+}
+
+func (c *rateCounter) record(now time.Time) {
+	slot := rateSlot(now)
+	bucket := &c.buckets[slot%rateBuckets]
+	if bucket.slot != slot {
+		bucket.slot = slot
+		bucket.count = 0
+	}
+	bucket.count++
+}
+
+func (c *rateCounter) rate(now time.Time, window time.Duration) float64 {
+	if window <= 0 {
+		return 0
+	}
+	if window > MaxRateWindow {
+		window = MaxRateWindow
+	}
+	last := rateSlot(now)
+	first := rateSlot(now.Add(-window))
+	count := 0
+	for _, bucket := range c.buckets {
+		if bucket.slot > first && bucket.slot <= last {
+			count += bucket.count
+		}
+	}
+	return float64(count) / window.Seconds()
+}
+
+func (b *bus) countEvent(topic string) {
+	if isReplyTopic(topic) {
+		return
+	}
+	counter, exists := b.rates[topic]
+	if !exists {
+		counter = &rateCounter{}
+		b.rates[topic] = counter
+	}
+	counter.record(time.Now())
+}
+
+// deliveryCounters count the outcome of deliveries to listeners of a topic.
+type deliveryCounters struct {
+	delivered uint64
+	panicked  uint64
+	dropped   uint64
+	queued    uint64
+	queueWait time.Duration
+}
+
+// TopicStats holds delivery statistics for a topic.
+type TopicStats struct {
+	// Delivered is the number of successful deliveries to listeners
+	Delivered uint64
+	// Panicked is the number of deliveries where the listener panicked,
+	// or returned an error
+	Panicked uint64
+	// Dropped is the number of events that were dropped, because the
+	// bus queue was full, or for listeners that were too slow
+	Dropped uint64
+	// AverageQueueWait is the average time events spent waiting in
+	// the bus queue before being dispatched
+	AverageQueueWait time.Duration
+}
+
+func (c deliveryCounters) successRate() float64 {
+	total := c.delivered + c.panicked + c.dropped
+	if total == 0 {
+		return 1
+	}
+	return float64(c.delivered) / float64(total)
+}
+
+// topicCounters returns the counters for a topic.
+// Must be called with the counters lock held.
+func (b *bus) topicCounters(topic string) *deliveryCounters {
+	if isReplyTopic(topic) {
+		// Reply topics are used once, and not kept in the stats
+		return &deliveryCounters{}
+	}
+	counters, exists := b.counters[topic]
+	if !exists {
+		counters = &deliveryCounters{}
+		b.counters[topic] = counters
+	}
+	return counters
+}
+
+// countQueueWait is called from the dispatcher for each dispatched event.
+func (b *bus) countQueueWait(topic string, wait time.Duration) {
+	b.countersLock.Lock()
+	defer b.countersLock.Unlock()
+
+	counters := b.topicCounters(topic)
+	counters.queued++
+	counters.queueWait += wait
+}
+
+// countDelivery is called from listener goroutines after each delivery.
+func (b *bus) countDelivery(topic string, err error) {
+	b.countersLock.Lock()
+	counters := b.topicCounters(topic)
+	if err != nil {
+		counters.panicked++
+	} else {
+		counters.delivered++
+	}
+	b.countersLock.Unlock()
+
+	if err != nil {
+		b.emit(ListenerPanicked, topic, 1)
+	} else {
+		b.emit(EventDelivered, topic, 1)
+	}
+}
+
+// countDrop is called when an event is dropped.
+func (b *bus) countDrop(topic string) {
+	b.countersLock.Lock()
+	b.topicCounters(topic).dropped++
+	b.countersLock.Unlock()
+
+	b.emit(EventDropped, topic, 1)
+}
+
+func (b *bus) Stats(topic string) TopicStats {
+	b.countersLock.Lock()
+	defer b.countersLock.Unlock()
+
+	var stats TopicStats
+	if counters, exists := b.counters[topic]; exists {
+		stats.Delivered = counters.delivered
+		stats.Panicked = counters.panicked
+		stats.Dropped = counters.dropped
+		if counters.queued > 0 {
+			stats.AverageQueueWait = counters.queueWait / time.Duration(counters.queued)
+		}
+	}
+	return stats
+}
+
+func (b *bus) SuccessRate(topic string) float64 {
+	b.countersLock.Lock()
+	defer b.countersLock.Unlock()
+
+	if counters, exists := b.counters[topic]; exists {
+		return counters.successRate()
+	}
+	return 1
+}
+
+// DiagnosticsInfo describes the resource usage of a bus.
+type DiagnosticsInfo struct {
+	// ListenerGoroutines is the number of running listener goroutines
+	ListenerGoroutines int
+	// BufferedEvents is the number of events handed to listeners,
+	// that they have not yet started processing
+	BufferedEvents int
+	// ProcessedRequests is the number of requests processed by the
+	// bus dispatcher
+	ProcessedRequests uint64
+}
+
+func (b *bus) Diagnostics() DiagnosticsInfo {
+	var info DiagnosticsInfo
+	b.runQuery(func() {
+		for _, listeners := range b.topicListeners {
+			for _, l := range listeners {
+				info.BufferedEvents += len(l.channel)
+				if l.pending != nil {
+					info.BufferedEvents += l.pending.size()
+				}
+			}
+		}
+		info.ProcessedRequests = b.processed
+	})
+	info.ListenerGoroutines = int(atomic.LoadInt32(&b.goroutines))
+	return info
+}
@@ -0,0 +1,132 @@
+package eventually_test
+
+import (
+	events "github.com/erkkah/eventually"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateStats(t *testing.T) {
+	b := events.NewBus()
+
+	for i := 0; i < 20; i++ {
+		b.Post("tick", i)
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	rate := b.RateStats("tick", 200*time.Millisecond)
+	if rate < 50 || rate > 150 {
+		t.Fatalf("Expected a rate of about 100 events/s, got %v", rate)
+	}
+
+	if rate := b.RateStats("tock", time.Second); rate != 0 {
+		t.Fatalf("Expected zero rate for unused topic, got %v", rate)
+	}
+}
+
+func TestSuccessRate(t *testing.T) {
+	b := events.NewBus()
+
+	var handled sync.WaitGroup
+	b.OnError(func(topic string, err error) {
+		handled.Done()
+	})
+
+	b.On("flaky", func(i int) {
+		defer handled.Done()
+		if i%2 == 0 {
+			panic("Even")
+		}
+	})
+
+	const posts = 10
+	// Each panic is also handled by the error handler.
+	// The last event panics, making sure all deliveries are
+	// counted once the last error has been handled.
+	handled.Add(posts + posts/2)
+	for i := 1; i <= posts; i++ {
+		b.Post("flaky", i)
+	}
+	handled.Wait()
+
+	if rate := b.SuccessRate("flaky"); rate != 0.5 {
+		t.Fatalf("Expected a success rate of 0.5, got %v", rate)
+	}
+
+	if rate := b.SuccessRate("unused"); rate != 1 {
+		t.Fatalf("Expected a success rate of 1 for unused topic, got %v", rate)
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	b := events.NewBus(events.WithTopicOrder("stack", events.LIFO))
+
+	for i := 0; i < 3; i++ {
+		b.On("ping", func() {})
+	}
+
+	started := make(chan bool)
+	release := make(chan bool)
+	b.On("stack", func(msg int) {
+		if msg == 1 {
+			started <- true
+			<-release
+		}
+	})
+
+	b.Post("stack", 1)
+	<-started
+
+	before := b.Diagnostics()
+
+	b.Post("stack", 2)
+	b.Post("stack", 3)
+	b.Post("stack", 4)
+	// Let the last event settle on the pending stack
+	time.Sleep(10 * time.Millisecond)
+
+	diagnostics := b.Diagnostics()
+	close(release)
+
+	// Three plain listeners, and two goroutines for the LIFO listener
+	if diagnostics.ListenerGoroutines != 5 {
+		t.Fatalf("Expected 5 listener goroutines, got %d", diagnostics.ListenerGoroutines)
+	}
+	if diagnostics.BufferedEvents != 3 {
+		t.Fatalf("Expected 3 buffered events, got %d", diagnostics.BufferedEvents)
+	}
+	// Three posts, and the first diagnostics request
+	if processed := diagnostics.ProcessedRequests - before.ProcessedRequests; processed != 4 {
+		t.Fatalf("Expected 4 processed requests, got %d", processed)
+	}
+}
+
+func TestQueueWaitStats(t *testing.T) {
+	b := events.NewBus()
+
+	release := make(chan bool)
+	b.On("slow", func() {
+		<-release
+	})
+
+	b.Post("slow")
+	// Stall the dispatcher on delivering to the busy listener
+	go b.Post("slow")
+	time.Sleep(5 * time.Millisecond)
+
+	probed := make(chan bool)
+	go func() {
+		b.Post("probe")
+		probed <- true
+	}()
+
+	const stall = 50 * time.Millisecond
+	time.Sleep(stall)
+	close(release)
+	<-probed
+
+	if wait := b.Stats("probe").AverageQueueWait; wait < stall-10*time.Millisecond {
+		t.Fatalf("Expected queue wait of at least %v, got %v", stall, wait)
+	}
+}
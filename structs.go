@@ -0,0 +1,104 @@
+package eventually
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ErrNotStruct is returned when posting a value that is not a struct
+// using PostStruct.
+var ErrNotStruct = errors.New("Event is not a struct")
+
+func (b *bus) PostStruct(topic string, event interface{}) error {
+	if event == nil || reflect.TypeOf(event).Kind() != reflect.Struct {
+		return ErrNotStruct
+	}
+	return b.Post(topic, event)
+}
+
+func (b *bus) OnStruct(topic string, callback interface{}) (Listener, error) {
+	callbackType := reflect.TypeOf(callback)
+	if callbackType.Kind() != reflect.Func || callbackType.NumIn() != 1 ||
+		callbackType.In(0).Kind() != reflect.Struct || callbackType.NumOut() != 0 {
+		panic("Struct listeners must be functions taking a single struct")
+	}
+	l := buildListener(topic, callback, false)
+	l.structured = true
+	l.invoke = structInvoker(reflect.ValueOf(callback), callbackType.In(0))
+	return b.addListenerRequest(b.startListener(l))
+}
+
+// structInvoker returns a function calling a callback with a struct
+// populated from event arguments.
+func structInvoker(callback reflect.Value, structType reflect.Type) func(args []interface{}) {
+	return func(args []interface{}) {
+		if len(args) == 1 && args[0] != nil && reflect.TypeOf(args[0]).AssignableTo(structType) {
+			// Posted with PostStruct, or otherwise as the struct itself
+			callback.Call([]reflect.Value{reflect.ValueOf(args[0])})
+			return
+		}
+		value := reflect.New(structType).Elem()
+		if len(args) == 1 {
+			if named, isNamed := args[0].(map[string]interface{}); isNamed {
+				populateByName(value, named)
+				callback.Call([]reflect.Value{value})
+				return
+			}
+		}
+		populateByPosition(value, args)
+		callback.Call([]reflect.Value{value})
+	}
+}
+
+// populateByName sets struct fields from named arguments, matching
+// the event tag of each field, or its name.
+func populateByName(value reflect.Value, named map[string]interface{}) {
+	structType := value.Type()
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name := field.Tag.Get("event")
+		if name == "" {
+			name = field.Name
+		}
+		for key, arg := range named {
+			if strings.EqualFold(key, name) {
+				setField(value.Field(i), field.Name, arg)
+				break
+			}
+		}
+	}
+}
+
+// populateByPosition sets the exported struct fields, in order,
+// from positional arguments.
+func populateByPosition(value reflect.Value, args []interface{}) {
+	structType := value.Type()
+	next := 0
+	for i := 0; i < structType.NumField() && next < len(args); i++ {
+		field := structType.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		setField(value.Field(i), field.Name, args[next])
+		next++
+	}
+	if next < len(args) {
+		panic(fmt.Sprintf("%d arguments do not fit struct %v", len(args), structType))
+	}
+}
+
+func setField(field reflect.Value, name string, arg interface{}) {
+	if arg == nil {
+		return
+	}
+	argValue := reflect.ValueOf(arg)
+	if !argValue.Type().AssignableTo(field.Type()) {
+		panic(fmt.Sprintf("cannot assign %v to field %s of type %v", argValue.Type(), name, field.Type()))
+	}
+	field.Set(argValue)
+}
@@ -0,0 +1,94 @@
+package eventually_test
+
+import (
+	events "github.com/erkkah/eventually"
+	"testing"
+)
+
+type person struct {
+	Name  string
+	Age   int
+	Email string `event:"mail"`
+}
+
+func TestOnStruct(t *testing.T) {
+	b := events.NewBus()
+
+	received := make(chan person, 3)
+	_, err := b.OnStruct("person", func(p person) {
+		received <- p
+	})
+	if err != nil {
+		t.Fatalf("Failed to register struct callback: %v", err)
+	}
+
+	b.Post("person", map[string]interface{}{"Age": 42, "Name": "Fred"})
+	b.Post("person", map[string]interface{}{"mail": "jo@example.com", "name": "Jo", "age": 7})
+	b.Post("person", "Ann", 30)
+
+	expected := []person{
+		{Name: "Fred", Age: 42},
+		{Name: "Jo", Age: 7, Email: "jo@example.com"},
+		{Name: "Ann", Age: 30},
+	}
+	for _, e := range expected {
+		if p := <-received; p != e {
+			t.Fatalf("Expected %+v, got %+v", e, p)
+		}
+	}
+}
+
+func TestPostStruct(t *testing.T) {
+	type address struct {
+		Street string
+	}
+	b := events.NewBus(events.WithEventMap(events.EventMap{
+		"person": {person{}},
+	}))
+
+	received := make(chan person, 1)
+	_, err := b.On("person", func(p person) {
+		received <- p
+	})
+	if err != nil {
+		t.Fatalf("Failed to register callback: %v", err)
+	}
+	if _, err := b.On("person", func(a address) {}); err == nil {
+		t.Fatal("Registering callback taking another struct should fail")
+	}
+
+	fred := person{Name: "Fred", Age: 42}
+	if err := b.PostStruct("person", fred); err != nil {
+		t.Fatalf("Failed to post struct: %v", err)
+	}
+	if p := <-received; p != fred {
+		t.Fatalf("Expected %+v, got %+v", fred, p)
+	}
+
+	if err := b.PostStruct("person", address{"Main St"}); err == nil {
+		t.Fatal("Posting another struct should fail")
+	}
+	if err := b.PostStruct("person", "Fred"); err != events.ErrNotStruct {
+		t.Fatalf("Expected ErrNotStruct, got %v", err)
+	}
+}
+
+func TestPostStructOnStruct(t *testing.T) {
+	b := events.NewBus()
+
+	received := make(chan person, 1)
+	_, err := b.OnStruct("person", func(p person) {
+		received <- p
+	})
+	if err != nil {
+		t.Fatalf("Failed to register struct callback: %v", err)
+	}
+
+	fred := person{Name: "Fred", Age: 42, Email: "fred@example.com"}
+	if err := b.PostStruct("person", fred); err != nil {
+		t.Fatalf("Failed to post struct: %v", err)
+	}
+	if p := <-received; p != fred {
+		t.Fatalf("Expected %+v, got %+v", fred, p)
+	}
+}
@@ -0,0 +1,114 @@
+package eventually
+
+import (
+	"sync"
+	"time"
+)
+
+// WithSyncDispatch makes Post call listener callbacks directly on the
+// posting goroutine, one at a time, and return when all of them are done.
+// Callbacks are only called one at a time per Post: with concurrent
+// posters, the same callback can run concurrently, and receive the
+// events of different posters in any order.
+// Errors from failing callbacks are returned from Post, instead of being
+// passed to the error handler.
+//
+// Synchronous dispatch removes the isolation provided by listener
+// goroutines: a slow callback blocks the poster, and callbacks run in
+// registration order instead of concurrently.
+// Events posted using PostNoWait or PostMulti, or held while the bus was
+// paused, are delivered in order on a separate goroutine, with errors
+// passed to the error handler.
+func WithSyncDispatch() Option {
+	return func(b *bus) {
+		b.syncDispatch = true
+	}
+}
+
+// handoff is an event to be handed to a listener.
+type handoff struct {
+	listener Listener
+	args     []interface{}
+}
+
+// postSync posts an event, and delivers it on the calling goroutine.
+func (b *bus) postSync(evnt event) error {
+	enqueued := time.Now()
+	var deliveries []handoff
+	var err error
+	closedErr := b.runQuery(func() {
+		b.countQueueWait(evnt.topic, time.Since(enqueued))
+		err = b.sendEvent(evnt)
+		deliveries = b.syncDeliveries
+		b.syncDeliveries = nil
+	})
+	if closedErr != nil {
+		return closedErr
+	}
+	if err != nil {
+		return err
+	}
+
+	errs := errorList{}
+	for _, delivery := range deliveries {
+		err := b.invoke(delivery.listener, delivery.args)
+		b.handled(delivery.listener)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// syncBacklog holds events collected for synchronous delivery that have
+// no waiting poster, until they are delivered.
+type syncBacklog struct {
+	lock       sync.Mutex
+	deliveries []handoff
+	running    bool
+}
+
+// flushSyncDeliveries delivers events collected for synchronous delivery
+// that have no waiting poster, in order, on a separate goroutine.
+// Called on the dispatcher goroutine.
+func (b *bus) flushSyncDeliveries() {
+	if len(b.syncDeliveries) == 0 {
+		return
+	}
+	backlog := &b.syncBacklog
+	backlog.lock.Lock()
+	backlog.deliveries = append(backlog.deliveries, b.syncDeliveries...)
+	start := !backlog.running
+	backlog.running = true
+	backlog.lock.Unlock()
+	b.syncDeliveries = nil
+
+	// A single goroutine at a time drains the backlog, keeping the order
+	if start {
+		b.goListener(b.drainSyncBacklog)
+	}
+}
+
+// drainSyncBacklog delivers backlogged events until there are none left.
+func (b *bus) drainSyncBacklog() {
+	backlog := &b.syncBacklog
+	for {
+		backlog.lock.Lock()
+		deliveries := backlog.deliveries
+		backlog.deliveries = nil
+		if len(deliveries) == 0 {
+			backlog.running = false
+		}
+		backlog.lock.Unlock()
+
+		if len(deliveries) == 0 {
+			return
+		}
+		for _, delivery := range deliveries {
+			b.deliver(delivery.listener, delivery.args)
+		}
+	}
+}
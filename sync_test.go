@@ -0,0 +1,90 @@
+package eventually_test
+
+import (
+	"fmt"
+	events "github.com/erkkah/eventually"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithSyncDispatch(t *testing.T) {
+	b := events.NewBus(events.WithSyncDispatch())
+
+	var calls []string
+	b.On("step", func(name string) {
+		calls = append(calls, "first "+name)
+	})
+	b.On("step", func(name string) {
+		calls = append(calls, "second "+name)
+	})
+
+	if err := b.Post("step", "one"); err != nil {
+		t.Fatalf("Failed to post event: %v", err)
+	}
+	// No synchronization needed, callbacks have run when Post returns
+	expected := []string{"first one", "second one"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Fatalf("Expected %v, got %v", expected, calls)
+	}
+
+	b.On("crash", func() {
+		panic("Crash")
+	})
+	if err := b.Post("crash"); err == nil {
+		t.Fatal("Expected listener panic to be returned from Post")
+	}
+}
+
+func TestPostSync(t *testing.T) {
+	b := events.NewBus()
+
+	var handled int32
+	b.On("flush", func() {
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&handled, 1)
+	})
+	b.On("flush", func() error {
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&handled, 1)
+		return fmt.Errorf("Flush failed")
+	})
+
+	err := b.PostSync("flush")
+	if n := atomic.LoadInt32(&handled); n != 2 {
+		t.Fatalf("Expected both listeners to be done, %d were", n)
+	}
+	if err == nil || err.Error() != "Flush failed" {
+		t.Fatalf("Expected listener error from PostSync, got %v", err)
+	}
+}
+
+func TestWithSyncDispatch_Subscribe(t *testing.T) {
+	testSubscribeCloses(t, events.NewBus(events.WithSyncDispatch()))
+}
+
+func TestWithSyncDispatch_NoWaitOrder(t *testing.T) {
+	b := events.NewBus(events.WithSyncDispatch(), events.WithQueueLength(100))
+
+	const posts = 2000
+	received := make([]int, 0, posts)
+	done := make(chan bool)
+	b.On("count", func(i int) {
+		received = append(received, i)
+		if len(received) == posts {
+			close(done)
+		}
+	})
+
+	for i := 0; i < posts; i++ {
+		b.PostNoWait("count", i)
+	}
+	<-done
+
+	for i, n := range received {
+		if n != i {
+			t.Fatalf("Expected event %d, got %d", i, n)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package eventually
+
+import (
+	"container/heap"
+	"fmt"
+	"time"
+)
+
+// WithTimeOrdering makes a topic hold events until their effective time,
+// set using PostAt, and deliver them in effective time order.
+// Events posted to the topic without an effective time take effect
+// when posted.
+func WithTimeOrdering(topic string) Option {
+	return func(b *bus) {
+		if b.timeOrdered == nil {
+			b.timeOrdered = make(map[string]*timeQueue)
+		}
+		b.timeOrdered[topic] = &timeQueue{}
+	}
+}
+
+// timeQueue holds events of a time ordered topic, ordered by effective time.
+// Time queues are owned by the dispatcher goroutine.
+type timeQueue []event
+
+func (q timeQueue) Len() int {
+	return len(q)
+}
+
+func (q timeQueue) Less(i, j int) bool {
+	return q[i].effective.Before(q[j].effective)
+}
+
+func (q timeQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+func (q *timeQueue) Push(x interface{}) {
+	*q = append(*q, x.(event))
+}
+
+func (q *timeQueue) Pop() interface{} {
+	old := *q
+	last := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return last
+}
+
+func (b *bus) PostAt(effective time.Time, topic string, data ...interface{}) error {
+	if _, ordered := b.timeOrdered[topic]; !ordered {
+		return fmt.Errorf("Topic %q is not time ordered", topic)
+	}
+	return b.postEvent(event{
+		topic:     topic,
+		data:      data,
+		effective: effective,
+	})
+}
+
+// holdUntilEffective holds an event until its effective time.
+func (b *bus) holdUntilEffective(queue *timeQueue, evnt event) {
+	evnt.tracker = nil
+	now := time.Now()
+	if evnt.effective.IsZero() {
+		evnt.effective = now
+	}
+	heap.Push(queue, evnt)
+	if !evnt.effective.After(now) {
+		b.releaseEffective(queue)
+		return
+	}
+	time.AfterFunc(evnt.effective.Sub(now), func() {
+		b.runQuery(func() {
+			b.releaseEffective(queue)
+		})
+	})
+}
+
+// releaseEffective delivers the held events that have taken effect.
+func (b *bus) releaseEffective(queue *timeQueue) {
+	now := time.Now()
+	for queue.Len() > 0 && !(*queue)[0].effective.After(now) {
+		b.publishRecovered(heap.Pop(queue).(event))
+	}
+}
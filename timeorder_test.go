@@ -0,0 +1,73 @@
+package eventually_test
+
+import (
+	events "github.com/erkkah/eventually"
+	"testing"
+	"time"
+)
+
+func TestWithTimeOrdering(t *testing.T) {
+	b := events.NewBus(events.WithTimeOrdering("tick"))
+
+	type delivery struct {
+		name string
+		at   time.Time
+	}
+	received := make(chan delivery, 3)
+	b.On("tick", func(name string) {
+		received <- delivery{name, time.Now()}
+	})
+
+	start := time.Now()
+	offsets := map[string]time.Duration{
+		"third":  60 * time.Millisecond,
+		"first":  20 * time.Millisecond,
+		"second": 40 * time.Millisecond,
+	}
+	for _, name := range []string{"third", "first", "second"} {
+		if err := b.PostAt(start.Add(offsets[name]), "tick", name); err != nil {
+			t.Fatalf("Failed to post event: %v", err)
+		}
+	}
+
+	for _, expected := range []string{"first", "second", "third"} {
+		d := <-received
+		if d.name != expected {
+			t.Fatalf("Expected %q, got %q", expected, d.name)
+		}
+		if early := start.Add(offsets[expected]).Sub(d.at); early > 0 {
+			t.Fatalf("Event %q delivered %v early", d.name, early)
+		}
+	}
+
+	if err := b.PostAt(start, "untimed"); err == nil {
+		t.Fatal("Expected posting with effective time to untimed topic to fail")
+	}
+}
+
+func TestWithTimeOrdering_PanicOnRelease(t *testing.T) {
+	failed := make(chan error, 1)
+	b := events.NewBus(
+		events.WithTimeOrdering("t"),
+		events.WithErrorHandler(func(topic string, err error) {
+			failed <- err
+		}),
+	)
+
+	b.OnFiltered("t", func(data []interface{}) bool {
+		panic("Broken filter")
+	}, func() {})
+
+	if err := b.PostAt(time.Now().Add(10*time.Millisecond), "t"); err != nil {
+		t.Fatalf("Failed to post event: %v", err)
+	}
+
+	select {
+	case <-failed:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the failed release to be reported")
+	}
+	if _, found := b.BrokenTopics()["t"]; !found {
+		t.Fatal("Expected the topic to be broken")
+	}
+}
@@ -0,0 +1,31 @@
+package eventually
+
+// Topic is a topic name, for declaring topics as constants, to have
+// misspelled topics caught by the compiler:
+//
+//	const UserCreated eventually.Topic = "user.created"
+//
+//	bus.OnT(UserCreated, func(name string) {})
+//	bus.PostT(UserCreated, "fred")
+type Topic string
+
+// TopicMap is an event map keyed by Topic values.
+type TopicMap map[Topic][]interface{}
+
+// EventMap returns the topic map as an EventMap, to be used with
+// WithEventMap and SetEventMap.
+func (m TopicMap) EventMap() EventMap {
+	eventMap := make(EventMap, len(m))
+	for topic, args := range m {
+		eventMap[string(topic)] = args
+	}
+	return eventMap
+}
+
+func (b *bus) OnT(topic Topic, callback interface{}) (Listener, error) {
+	return b.On(string(topic), callback)
+}
+
+func (b *bus) PostT(topic Topic, data ...interface{}) error {
+	return b.Post(string(topic), data...)
+}
@@ -0,0 +1,33 @@
+package eventually_test
+
+import (
+	events "github.com/erkkah/eventually"
+	"testing"
+)
+
+const userCreated events.Topic = "user.created"
+
+func TestTopicConstants(t *testing.T) {
+	b := events.NewBus(events.WithEventMap(events.TopicMap{
+		userCreated: {"name"},
+	}.EventMap()))
+
+	received := make(chan string, 1)
+	_, err := b.OnT(userCreated, func(name string) {
+		received <- name
+	})
+	if err != nil {
+		t.Fatalf("Failed to register callback: %v", err)
+	}
+
+	if err := b.PostT(userCreated, "fred"); err != nil {
+		t.Fatalf("Failed to post event: %v", err)
+	}
+	if name := <-received; name != "fred" {
+		t.Fatalf("Unexpected name %q", name)
+	}
+
+	if err := b.PostT(userCreated, 42); err == nil {
+		t.Fatal("Expected event map to apply to typed topics")
+	}
+}
@@ -0,0 +1,163 @@
+package eventually
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// ListenerInfo describes a registered listener.
+type ListenerInfo struct {
+	// ID is the id of the listener
+	ID uint64
+	// Topic is the topic, or topic pattern, of the listener
+	Topic string
+	// Tag is the tag of listeners registered using OnTagged
+	Tag string
+	// Group is the group of listeners registered using OnGrouped
+	Group string
+	// Delivered is the number of events successfully handled by
+	// the listener
+	Delivered uint64
+}
+
+func (b *bus) Listeners() []ListenerInfo {
+	var infos []ListenerInfo
+	b.runQuery(func() {
+		for topic, listeners := range b.topicListeners {
+			for _, l := range listeners {
+				infos = append(infos, ListenerInfo{
+					ID:        l.id,
+					Topic:     topic,
+					Tag:       l.tag,
+					Group:     l.groupName,
+					Delivered: atomic.LoadUint64(l.delivered),
+				})
+			}
+		}
+	})
+	sort.SliceStable(infos, func(i, j int) bool {
+		return infos[i].Topic < infos[j].Topic
+	})
+	return infos
+}
+
+func (b *bus) SubscriberCount(topic string) int {
+	var count int
+	b.runQuery(func() {
+		count = len(b.topicListeners[topic])
+	})
+	return count
+}
+
+func (b *bus) HasListeners(topic string) bool {
+	var found bool
+	b.runQuery(func() {
+		found = b.hasListeners(topic)
+	})
+	return found
+}
+
+func (b *bus) Topics() []string {
+	topics := []string{}
+	b.runQuery(func() {
+		for topic, listeners := range b.topicListeners {
+			if len(listeners) > 0 {
+				topics = append(topics, topic)
+			}
+		}
+	})
+	sort.Strings(topics)
+	return topics
+}
+
+// AuditReport lists mismatches between the event map of a bus
+// and its listeners.
+type AuditReport struct {
+	// Unused lists the sorted topics in the event map without listeners,
+	// including wildcard listeners
+	Unused []string
+	// Undeclared lists the sorted topics with listeners that are not in
+	// the event map, like topics with verifiers. Without an event map,
+	// all topics with listeners are undeclared.
+	Undeclared []string
+}
+
+func (b *bus) Audit() AuditReport {
+	report := AuditReport{
+		Unused:     []string{},
+		Undeclared: []string{},
+	}
+	b.runQuery(func() {
+		declared := EventMap{}
+		if b.eventMap != nil {
+			declared = *b.eventMap
+		}
+		for topic := range declared {
+			if !b.hasListeners(topic) {
+				report.Unused = append(report.Unused, topic)
+			}
+		}
+		for topic, listeners := range b.topicListeners {
+			if len(listeners) == 0 || isPattern(topic) || isReplyTopic(topic) {
+				continue
+			}
+			if _, found := declared[topic]; !found {
+				report.Undeclared = append(report.Undeclared, topic)
+			}
+		}
+	})
+	sort.Strings(report.Unused)
+	sort.Strings(report.Undeclared)
+	return report
+}
+
+// TopologyDOT renders the topics of a bus and their listeners as a
+// Graphviz DOT graph.
+// Topics are labelled with their number of listeners. Tagged and grouped
+// listeners are shown as separate nodes, connected to their topics.
+func TopologyDOT(b Bus) string {
+	counts := map[string]int{}
+	var topics []string
+	named := map[string]string{}
+	edges := map[string]bool{}
+	for _, info := range b.Listeners() {
+		if counts[info.Topic] == 0 {
+			topics = append(topics, info.Topic)
+		}
+		counts[info.Topic]++
+		if info.Tag != "" {
+			named["listener:"+info.Tag] = info.Tag
+			edges[fmt.Sprintf("%q -> %q", "topic:"+info.Topic, "listener:"+info.Tag)] = true
+		}
+		if info.Group != "" {
+			named["group:"+info.Group] = info.Group
+			edges[fmt.Sprintf("%q -> %q", "topic:"+info.Topic, "group:"+info.Group)] = true
+		}
+	}
+
+	var dot strings.Builder
+	dot.WriteString("digraph eventually {\n")
+	for _, topic := range topics {
+		label := fmt.Sprintf("%s (%d)", topic, counts[topic])
+		fmt.Fprintf(&dot, "\t%q [shape=box, label=%q];\n", "topic:"+topic, label)
+	}
+	for _, node := range sortedKeys(named) {
+		fmt.Fprintf(&dot, "\t%q [label=%q];\n", node, named[node])
+	}
+	for _, edge := range sortedKeys(edges) {
+		fmt.Fprintf(&dot, "\t%s;\n", edge)
+	}
+	dot.WriteString("}\n")
+	return dot.String()
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,133 @@
+package eventually_test
+
+import (
+	events "github.com/erkkah/eventually"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTopologyDOT(t *testing.T) {
+	b := events.NewBus()
+
+	b.OnTagged("user.created", "audit", func() {})
+	b.OnTagged("user.deleted", "audit", func() {})
+	b.OnTagged("user.created", "mailer", func() {})
+	b.On("user.created", func() {})
+
+	dot := events.TopologyDOT(b)
+
+	expected := []string{
+		`"topic:user.created" [shape=box, label="user.created (3)"];`,
+		`"topic:user.deleted" [shape=box, label="user.deleted (1)"];`,
+		`"listener:audit" [label="audit"];`,
+		`"listener:mailer" [label="mailer"];`,
+		`"topic:user.created" -> "listener:audit";`,
+		`"topic:user.deleted" -> "listener:audit";`,
+		`"topic:user.created" -> "listener:mailer";`,
+	}
+	if !strings.HasPrefix(dot, "digraph eventually {") {
+		t.Fatalf("Expected a digraph, got:\n%s", dot)
+	}
+	for _, line := range expected {
+		if !strings.Contains(dot, line) {
+			t.Errorf("Expected %s in:\n%s", line, dot)
+		}
+	}
+}
+
+func TestSubscriberCountAndTopics(t *testing.T) {
+	b := events.NewBus()
+
+	b.On("beta", func() {})
+	b.On("alpha", func() {})
+	removed, _ := b.On("alpha", func() {})
+	gone, _ := b.On("gamma", func() {})
+
+	if count := b.SubscriberCount("alpha"); count != 2 {
+		t.Fatalf("Expected 2 subscribers, got %d", count)
+	}
+
+	b.Unsubscribe("alpha", removed)
+	b.Unsubscribe("gamma", gone)
+
+	if count := b.SubscriberCount("alpha"); count != 1 {
+		t.Fatalf("Expected 1 subscriber after unsubscribing, got %d", count)
+	}
+	if count := b.SubscriberCount("none"); count != 0 {
+		t.Fatalf("Expected no subscribers, got %d", count)
+	}
+	if topics := b.Topics(); !reflect.DeepEqual(topics, []string{"alpha", "beta"}) {
+		t.Fatalf("Unexpected topics: %v", topics)
+	}
+}
+
+func TestHasListeners(t *testing.T) {
+	b := events.NewBus()
+
+	if b.HasListeners("user.created") {
+		t.Fatal("Expected no listeners on a new bus")
+	}
+
+	listener, _ := b.On("user.*", func(string) {})
+	if !b.HasListeners("user.created") {
+		t.Fatal("Expected wildcard listener to count")
+	}
+	if b.HasListeners("order.created") {
+		t.Fatal("Expected no listeners for unmatched topic")
+	}
+
+	b.Unsubscribe("user.*", listener)
+	if b.HasListeners("user.created") {
+		t.Fatal("Expected no listeners after unsubscribing")
+	}
+}
+
+func TestListenerDeliveredCount(t *testing.T) {
+	b := events.NewBus()
+
+	var delivered sync.WaitGroup
+	delivered.Add(6)
+	first, _ := b.On("ping", func() { delivered.Done() })
+	second, _ := b.On("ping", func() { delivered.Done() })
+
+	for i := 0; i < 3; i++ {
+		b.Post("ping")
+	}
+	delivered.Wait()
+	// Counts are updated after the callbacks return
+	time.Sleep(10 * time.Millisecond)
+
+	counts := map[uint64]uint64{}
+	for _, info := range b.Listeners() {
+		counts[info.ID] = info.Delivered
+	}
+	for _, l := range []events.Listener{first, second} {
+		if counts[l.ID()] != 3 {
+			t.Fatalf("Expected listener %d to have 3 deliveries, got %d", l.ID(), counts[l.ID()])
+		}
+	}
+}
+
+func TestAudit(t *testing.T) {
+	b := events.NewBus(
+		events.WithEventMap(events.EventMap{
+			"a": {},
+			"b": {},
+		}),
+		events.WithTopicVerifier("c", func([]interface{}) error { return nil }),
+	)
+
+	b.On("a", func() {})
+	b.On("c", func() {})
+
+	report := b.Audit()
+	if !reflect.DeepEqual(report.Unused, []string{"b"}) {
+		t.Fatalf("Expected b to be unused, got %v", report.Unused)
+	}
+	if !reflect.DeepEqual(report.Undeclared, []string{"c"}) {
+		t.Fatalf("Expected c to be undeclared, got %v", report.Undeclared)
+	}
+}
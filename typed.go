@@ -0,0 +1,41 @@
+package eventually
+
+// TypedTopic is a statically typed handle to a bus topic,
+// for events carrying a single argument of type T.
+type TypedTopic[T any] struct {
+	bus   Bus
+	topic string
+}
+
+// NewTypedTopic returns a typed handle to a topic on a bus.
+func NewTypedTopic[T any](bus Bus, topic string) TypedTopic[T] {
+	return TypedTopic[T]{
+		bus:   bus,
+		topic: topic,
+	}
+}
+
+// Topic returns the name of the topic.
+func (t TypedTopic[T]) Topic() string {
+	return t.topic
+}
+
+// On registers a callback that will receive all events until unsubscribed.
+func (t TypedTopic[T]) On(callback func(T)) (Listener, error) {
+	return t.bus.On(t.topic, callback)
+}
+
+// Once registers a callback that will receive at most one event.
+func (t TypedTopic[T]) Once(callback func(T)) (Listener, error) {
+	return t.bus.Once(t.topic, callback)
+}
+
+// Post sends an event to all listeners of the topic.
+func (t TypedTopic[T]) Post(value T) error {
+	return t.bus.Post(t.topic, value)
+}
+
+// Unsubscribe removes a listener registered using On or Once.
+func (t TypedTopic[T]) Unsubscribe(listener Listener) {
+	t.bus.Unsubscribe(t.topic, listener)
+}
@@ -0,0 +1,42 @@
+package eventually_test
+
+import (
+	events "github.com/erkkah/eventually"
+	"testing"
+	"time"
+)
+
+type temperature struct {
+	celsius float64
+}
+
+func TestTypedTopic(t *testing.T) {
+	b := events.NewBus(events.WithEventMap(events.EventMap{
+		"temperature": {temperature{}},
+	}))
+	topic := events.NewTypedTopic[temperature](b, "temperature")
+
+	received := make(chan temperature, 1)
+	listener, err := topic.On(func(reading temperature) {
+		received <- reading
+	})
+	if err != nil {
+		t.Fatalf("Failed to register typed listener: %v", err)
+	}
+
+	if err := topic.Post(temperature{21.5}); err != nil {
+		t.Fatalf("Failed to post typed event: %v", err)
+	}
+	if reading := <-received; reading.celsius != 21.5 {
+		t.Fatalf("Unexpected reading: %v", reading)
+	}
+
+	topic.Unsubscribe(listener)
+	topic.Post(temperature{-5})
+	time.Sleep(10 * time.Millisecond)
+	select {
+	case reading := <-received:
+		t.Fatalf("Unsubscribed listener received %v", reading)
+	default:
+	}
+}
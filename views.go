@@ -0,0 +1,234 @@
+package eventually
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrPostNotPermitted is returned when posting through a read-only bus view.
+var ErrPostNotPermitted = errors.New("Posting not permitted")
+
+// ErrSubscribeNotPermitted is returned when subscribing through a write-only bus view.
+var ErrSubscribeNotPermitted = errors.New("Subscribing not permitted")
+
+// ErrControlNotPermitted is returned when closing or reconfiguring the bus
+// through a bus view.
+var ErrControlNotPermitted = errors.New("Controlling the bus not permitted")
+
+type readOnlyBus struct {
+	Bus
+}
+
+// ReadOnly returns a view of the bus that can be used for listening,
+// but not for posting events.
+// All posts through the view fail with ErrPostNotPermitted.
+// Closing or reconfiguring the bus through the view fails with
+// ErrControlNotPermitted, or does nothing.
+func ReadOnly(b Bus) Bus {
+	return readOnlyBus{b}
+}
+
+// Clone clones the underlying bus, returning a read-only view of the clone.
+func (v readOnlyBus) Clone() Bus {
+	return ReadOnly(v.Bus.Clone())
+}
+
+func (readOnlyBus) Post(topic string, data ...interface{}) error {
+	return ErrPostNotPermitted
+}
+
+func (readOnlyBus) PostAt(effective time.Time, topic string, data ...interface{}) error {
+	return ErrPostNotPermitted
+}
+
+func (readOnlyBus) PostSync(topic string, data ...interface{}) error {
+	return ErrPostNotPermitted
+}
+
+func (readOnlyBus) PostT(topic Topic, data ...interface{}) error {
+	return ErrPostNotPermitted
+}
+
+func (readOnlyBus) PostWithID(id string, topic string, data ...interface{}) error {
+	return ErrPostNotPermitted
+}
+
+func (readOnlyBus) PostWithHeaders(topic string, headers map[string]string, data ...interface{}) error {
+	return ErrPostNotPermitted
+}
+
+// PostNoWait silently drops the event, since there is no way to
+// report the error.
+func (readOnlyBus) PostNoWait(topic string, data ...interface{}) {
+}
+
+func (readOnlyBus) PostMulti(topics []string, data ...interface{}) error {
+	return ErrPostNotPermitted
+}
+
+func (readOnlyBus) PostStruct(topic string, event interface{}) error {
+	return ErrPostNotPermitted
+}
+
+func (readOnlyBus) PostFunc(topic string, produce func() []interface{}) error {
+	return ErrPostNotPermitted
+}
+
+func (readOnlyBus) Close() error {
+	return ErrControlNotPermitted
+}
+
+func (readOnlyBus) Shutdown(ctx context.Context) error {
+	return ErrControlNotPermitted
+}
+
+func (readOnlyBus) SetEventMap(eventMap EventMap) ([]Listener, error) {
+	return nil, ErrControlNotPermitted
+}
+
+func (readOnlyBus) PauseAll() {
+}
+
+func (readOnlyBus) ResumeAll() {
+}
+
+func (readOnlyBus) RestartTopic(topic string) {
+}
+
+type writeOnlyBus struct {
+	Bus
+}
+
+// WriteOnly returns a view of the bus that can be used for posting,
+// but not for listening to events.
+// All subscriptions through the view fail with ErrSubscribeNotPermitted,
+// and unsubscribing does nothing.
+// Closing or reconfiguring the bus through the view fails with
+// ErrControlNotPermitted, or does nothing.
+func WriteOnly(b Bus) Bus {
+	return writeOnlyBus{b}
+}
+
+// Clone clones the underlying bus, returning a write-only view of the clone.
+func (v writeOnlyBus) Clone() Bus {
+	return WriteOnly(v.Bus.Clone())
+}
+
+func (writeOnlyBus) Once(topic string, callback interface{}) (Listener, error) {
+	return Listener{}, ErrSubscribeNotPermitted
+}
+
+func (writeOnlyBus) On(topic string, callback interface{}) (Listener, error) {
+	return Listener{}, ErrSubscribeNotPermitted
+}
+
+func (writeOnlyBus) OnMany(topics []string, callback interface{}) ([]Listener, error) {
+	return nil, ErrSubscribeNotPermitted
+}
+
+func (writeOnlyBus) OnceAsync(topic string, callback interface{}) (Listener, <-chan error) {
+	return Listener{}, notPermitted(ErrSubscribeNotPermitted)
+}
+
+func (writeOnlyBus) OnT(topic Topic, callback interface{}) (Listener, error) {
+	return Listener{}, ErrSubscribeNotPermitted
+}
+
+func (writeOnlyBus) OnAsync(topic string, callback interface{}) (Listener, <-chan error) {
+	return Listener{}, notPermitted(ErrSubscribeNotPermitted)
+}
+
+func (writeOnlyBus) OnWithID(topic string, callback func(id string, data ...interface{})) (Listener, error) {
+	return Listener{}, ErrSubscribeNotPermitted
+}
+
+func (writeOnlyBus) OnSeq(topic string, callback func(seq uint64, data ...interface{})) (Listener, error) {
+	return Listener{}, ErrSubscribeNotPermitted
+}
+
+func (writeOnlyBus) OnWithHeaders(topic string, callback func(headers map[string]string, data ...interface{})) (Listener, error) {
+	return Listener{}, ErrSubscribeNotPermitted
+}
+
+func (writeOnlyBus) OnHeaderMatch(topic string, required map[string]string, callback interface{}) (Listener, error) {
+	return Listener{}, ErrSubscribeNotPermitted
+}
+
+func (writeOnlyBus) OnAfterQuiet(deliverTopic string, quietTopic string, quiet time.Duration, callback interface{}) (Listener, error) {
+	return Listener{}, ErrSubscribeNotPermitted
+}
+
+func (writeOnlyBus) OnFiltered(topic string, filter func(data []interface{}) bool, callback interface{}) (Listener, error) {
+	return Listener{}, ErrSubscribeNotPermitted
+}
+
+func (writeOnlyBus) OnReduced(topic string, window time.Duration, reduce func(acc, next []interface{}) []interface{}, callback interface{}) (Listener, error) {
+	return Listener{}, ErrSubscribeNotPermitted
+}
+
+func (writeOnlyBus) OnSkipFirst(topic string, n int, callback interface{}) (Listener, error) {
+	return Listener{}, ErrSubscribeNotPermitted
+}
+
+func (writeOnlyBus) OnWithReplaySince(topic string, sinceSeq uint64, callback interface{}) (Listener, error) {
+	return Listener{}, ErrSubscribeNotPermitted
+}
+
+func (writeOnlyBus) OnStruct(topic string, callback interface{}) (Listener, error) {
+	return Listener{}, ErrSubscribeNotPermitted
+}
+
+func (writeOnlyBus) OnTagged(topic string, tag string, callback interface{}) (Listener, error) {
+	return Listener{}, ErrSubscribeNotPermitted
+}
+
+func (writeOnlyBus) OnGrouped(topic string, group string, callback interface{}) (Listener, error) {
+	return Listener{}, ErrSubscribeNotPermitted
+}
+
+func (writeOnlyBus) Subscribe(topic string, buffer int) (<-chan []interface{}, Listener, error) {
+	return nil, Listener{}, ErrSubscribeNotPermitted
+}
+
+func (writeOnlyBus) SubscribeChan(topic string, ch chan<- []interface{}) (Listener, error) {
+	return Listener{}, ErrSubscribeNotPermitted
+}
+
+func (writeOnlyBus) Unsubscribe(topic string, listener Listener) {
+}
+
+func (writeOnlyBus) UnsubscribeFunc(topic string, callback interface{}) {
+}
+
+func (writeOnlyBus) UnsubscribeDrain(listener Listener, ctx context.Context) error {
+	return ErrSubscribeNotPermitted
+}
+
+func (writeOnlyBus) Close() error {
+	return ErrControlNotPermitted
+}
+
+func (writeOnlyBus) Shutdown(ctx context.Context) error {
+	return ErrControlNotPermitted
+}
+
+func (writeOnlyBus) SetEventMap(eventMap EventMap) ([]Listener, error) {
+	return nil, ErrControlNotPermitted
+}
+
+func (writeOnlyBus) PauseAll() {
+}
+
+func (writeOnlyBus) ResumeAll() {
+}
+
+func (writeOnlyBus) RestartTopic(topic string) {
+}
+
+func notPermitted(err error) <-chan error {
+	result := make(chan error, 1)
+	result <- err
+	close(result)
+	return result
+}
@@ -0,0 +1,86 @@
+package eventually_test
+
+import (
+	"context"
+	events "github.com/erkkah/eventually"
+	"testing"
+)
+
+func TestReadOnly(t *testing.T) {
+	b := events.NewBus()
+	view := events.ReadOnly(b)
+
+	done := make(chan bool)
+	_, err := view.Once("ping", func() {
+		done <- true
+	})
+	if err != nil {
+		t.Fatalf("Failed to register callback: %v", err)
+	}
+
+	if err := view.Post("ping"); err != events.ErrPostNotPermitted {
+		t.Fatalf("Expected ErrPostNotPermitted, got: %v", err)
+	}
+
+	b.Post("ping")
+	<-done
+
+	testViewControl(t, b, view)
+}
+
+// testViewControl checks that a view cannot close or pause the bus.
+func testViewControl(t *testing.T, b events.Bus, view events.Bus) {
+	if err := view.Close(); err != events.ErrControlNotPermitted {
+		t.Fatalf("Expected ErrControlNotPermitted, got: %v", err)
+	}
+	if err := view.Shutdown(context.Background()); err != events.ErrControlNotPermitted {
+		t.Fatalf("Expected ErrControlNotPermitted, got: %v", err)
+	}
+	if _, err := view.SetEventMap(events.EventMap{}); err != events.ErrControlNotPermitted {
+		t.Fatalf("Expected ErrControlNotPermitted, got: %v", err)
+	}
+	view.PauseAll()
+
+	done := make(chan bool)
+	b.Once("pong", func() {
+		done <- true
+	})
+	if err := b.Post("pong"); err != nil {
+		t.Fatalf("Expected bus to stay open and running, got: %v", err)
+	}
+	<-done
+}
+
+func TestWriteOnly(t *testing.T) {
+	b := events.NewBus()
+	view := events.WriteOnly(b)
+
+	done := make(chan bool)
+	_, err := b.Once("ping", func() {
+		done <- true
+	})
+	if err != nil {
+		t.Fatalf("Failed to register callback: %v", err)
+	}
+
+	if err := view.Post("ping"); err != nil {
+		t.Fatalf("Failed to post event: %v", err)
+	}
+	<-done
+
+	_, err = view.On("ping", func() {})
+	if err != events.ErrSubscribeNotPermitted {
+		t.Fatalf("Expected ErrSubscribeNotPermitted, got: %v", err)
+	}
+
+	listener, _ := b.On("ping", func() {})
+	view.Unsubscribe("ping", listener)
+	if err := view.UnsubscribeDrain(listener, context.Background()); err != events.ErrSubscribeNotPermitted {
+		t.Fatalf("Expected ErrSubscribeNotPermitted, got: %v", err)
+	}
+	if count := b.SubscriberCount("ping"); count != 1 {
+		t.Fatalf("Expected listener to stay subscribed, found %d", count)
+	}
+
+	testViewControl(t, b, view)
+}
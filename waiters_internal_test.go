@@ -0,0 +1,24 @@
+package eventually
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWaitForListenersForgetsTimedOutWaiters(t *testing.T) {
+	b := NewBus().(*bus)
+
+	for i := 0; i < 10; i++ {
+		if err := b.WaitForListeners("ping", 1, time.Millisecond); err == nil {
+			t.Fatal("Waiting for missing listener should time out")
+		}
+	}
+
+	var waiters int
+	b.runQuery(func() {
+		waiters = len(b.waiters)
+	})
+	if waiters != 0 {
+		t.Fatalf("Expected timed out waiters to be removed, found waiters for %d topics", waiters)
+	}
+}
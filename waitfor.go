@@ -0,0 +1,37 @@
+package eventually
+
+import (
+	"fmt"
+	"time"
+)
+
+// handlerFunc adapts a function to the EventHandler interface.
+type handlerFunc func(topic string, data ...interface{})
+
+func (f handlerFunc) Handle(topic string, data ...interface{}) {
+	f(topic, data...)
+}
+
+// WaitFor blocks until the next event is posted to a topic, and returns
+// the event arguments.
+// Returns an error if no event is posted within the timeout.
+func WaitFor(b Bus, topic string, timeout time.Duration) ([]interface{}, error) {
+	received := make(chan []interface{}, 1)
+	listener, err := b.Once(topic, handlerFunc(func(_ string, data ...interface{}) {
+		received <- data
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case data := <-received:
+		return data, nil
+	case <-timer.C:
+		b.Unsubscribe(topic, listener)
+		return nil, fmt.Errorf("Timed out waiting for event on %q", topic)
+	}
+}
@@ -0,0 +1,64 @@
+package eventually
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// isPattern returns true for wildcard topic patterns, like "user.*".
+func isPattern(topic string) bool {
+	return strings.HasSuffix(topic, "*")
+}
+
+func matchesPattern(pattern string, topic string) bool {
+	return strings.HasPrefix(topic, strings.TrimSuffix(pattern, "*"))
+}
+
+var stringType = reflect.TypeOf("")
+
+// verifyWildcardListener checks that callbacks for wildcard listeners
+// take the topic as first argument.
+// Unless the callback is variadic, the remaining arguments are verified
+// against all topics in the event map matching the pattern.
+func (b *bus) verifyWildcardListener(l Listener) error {
+	if !l.typed() {
+		return nil
+	}
+	callbackType := l.callback.Type()
+	first := 0
+	if l.context != nil {
+		first = 1
+	}
+	if callbackType.NumIn() <= first || callbackType.In(first) != stringType {
+		return fmt.Errorf("Wildcard listeners must take the topic as first argument")
+	}
+	if b.eventMap == nil || callbackType.IsVariadic() {
+		return nil
+	}
+	argTypes := []reflect.Type{}
+	for i := first + 1; i < callbackType.NumIn(); i++ {
+		argTypes = append(argTypes, callbackType.In(i))
+	}
+	for topic, eventType := range *b.eventMap {
+		if !matchesPattern(l.topic, topic) {
+			continue
+		}
+		if isVariadic(eventType) || !reflect.DeepEqual(argTypes, declaredTypesOf(eventType)) {
+			return fmt.Errorf("Argument mismatch for topic %q", topic)
+		}
+	}
+	return nil
+}
+
+func (b *bus) hasListeners(topic string) bool {
+	if len(b.topicListeners[topic]) > 0 {
+		return true
+	}
+	for pattern := range b.patterns {
+		if matchesPattern(pattern, topic) && len(b.topicListeners[pattern]) > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package eventually_test
+
+import (
+	events "github.com/erkkah/eventually"
+	"testing"
+	"time"
+)
+
+func TestWildcard(t *testing.T) {
+	b := events.NewBus()
+
+	type received struct {
+		topic string
+		data  []interface{}
+	}
+	done := make(chan received, 10)
+
+	_, err := b.On("user.*", func(topic string, data ...interface{}) {
+		done <- received{topic, data}
+	})
+	if err != nil {
+		t.Fatalf("Failed to register callback: %v", err)
+	}
+
+	b.Post("user.created", "fred")
+	result := <-done
+	if result.topic != "user.created" {
+		t.Fatalf("Expected topic %q, got %q", "user.created", result.topic)
+	}
+	if len(result.data) != 1 || result.data[0] != "fred" {
+		t.Fatalf("Unexpected event data: %v", result.data)
+	}
+
+	b.Post("users", "fred")
+	select {
+	case result := <-done:
+		t.Fatalf("Unexpected event for topic %q", result.topic)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestWildcard_EventMap(t *testing.T) {
+	topics := events.EventMap{
+		"user.created": {"name"},
+		"user.deleted": {"name", 42},
+	}
+
+	b := events.NewBus(events.WithEventMap(topics))
+
+	done := make(chan string, 2)
+	_, err := b.On("user.*", func(topic string, data ...interface{}) {
+		done <- topic
+	})
+	if err != nil {
+		t.Fatalf("Failed to register callback: %v", err)
+	}
+
+	b.Post("user.created", "fred")
+	b.Post("user.deleted", "fred", 12)
+	if topic := <-done; topic != "user.created" {
+		t.Fatalf("Unexpected topic %q", topic)
+	}
+	if topic := <-done; topic != "user.deleted" {
+		t.Fatalf("Unexpected topic %q", topic)
+	}
+
+	_, err = b.On("user.*", func(age int) {})
+	if err == nil {
+		t.Fatal("Registering wildcard callback without topic argument should fail")
+	}
+
+	_, err = b.On("user.*", func(topic string, name string) {})
+	if err == nil {
+		t.Fatal("Registering wildcard callback not matching all topics should fail")
+	}
+
+	_, err = b.On("user.created*", func(topic string, name string) {})
+	if err != nil {
+		t.Fatalf("Failed to register callback matching all topics: %v", err)
+	}
+}